@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"flaremind/internal/audit"
+	"flaremind/internal/crawler"
+	"flaremind/internal/models"
+)
+
+// runAudit 站点审计模式：爬取站点后报告标题、描述、标题层级、重定向、内容过少等问题
+func runAudit(args []string) int {
+	fs := flag.NewFlagSet("audit", flag.ExitOnError)
+	targetURL := fs.String("url", "", "URL to audit (required)")
+	maxDepth := fs.Int("depth", 3, "Maximum crawl depth")
+	maxPages := fs.Int("pages", 100, "Maximum number of pages to crawl")
+	timeout := fs.Int("timeout", 60, "Timeout per page in seconds")
+	rateLimit := fs.Float64("rate", 2.0, "Maximum requests per second (0 = unlimited)")
+	delay := fs.Int("delay", 500, "Delay between requests in milliseconds")
+	sitemap := fs.String("sitemap", "auto", "Sitemap URL used to find orphan pages (\"auto\" = /sitemap.xml on the start host, empty = none)")
+	minWords := fs.Int("thin", 200, "Pages with fewer words than this are reported as thin content")
+	maxClicks := fs.Int("max-clicks", 3, "Pages more than this many clicks from the start URL are reported as deep")
+	outputFile := fs.String("o", "", "Write the report to this file (.md or .html); if not specified, Markdown is written to stdout")
+	jsonFile := fs.String("json", "", "Also write the report as JSON to this file")
+	fs.Parse(args)
+
+	if *targetURL == "" {
+		fmt.Fprintln(os.Stderr, "Error: URL is required")
+		fs.Usage()
+		return 2
+	}
+
+	parsedURL, err := parseURL(*targetURL)
+	if err != nil {
+		log.Printf("Invalid URL: %v", err)
+		return 2
+	}
+
+	sitemapURL := *sitemap
+	if sitemapURL == "auto" {
+		sitemapURL = parsedURL.Scheme + "://" + parsedURL.Host + "/sitemap.xml"
+	}
+
+	log.Printf("Auditing %s (depth: %d, pages: %d)", *targetURL, *maxDepth, *maxPages)
+
+	manager := newCrawlManager(*timeout, crawler.NewExtractor(), crawler.NewConverter())
+	config := models.CrawlConfig{
+		MaxDepth:       *maxDepth,
+		MaxPages:       *maxPages,
+		AllowedDomains: []string{parsedURL.Host},
+		MaxWorkers:     5,
+		Timeout:        *timeout,
+		RateLimit:      *rateLimit,
+		Delay:          *delay,
+		SitemapURL:     sitemapURL,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(*timeout**maxPages+60)*time.Second)
+	defer cancel()
+
+	pages, err := manager.Crawl(ctx, *targetURL, config)
+	if err != nil {
+		log.Printf("Crawl failed: %v", err)
+		return 2
+	}
+
+	report := audit.Audit(*targetURL, pages, audit.Config{
+		MinWords:      *minWords,
+		MaxClickDepth: *maxClicks,
+	})
+
+	if *jsonFile != "" {
+		if err := writeAuditFile(*jsonFile, report, audit.WriteJSON); err != nil {
+			log.Printf("Failed to write JSON report: %v", err)
+			return 2
+		}
+		log.Printf("JSON report saved to: %s", *jsonFile)
+	}
+
+	switch {
+	case *outputFile == "":
+		if err := audit.WriteMarkdown(os.Stdout, report); err != nil {
+			log.Printf("Failed to write report: %v", err)
+			return 2
+		}
+	default:
+		write := audit.WriteMarkdown
+		if ext := strings.ToLower(filepath.Ext(*outputFile)); ext == ".html" || ext == ".htm" {
+			write = audit.WriteHTML
+		}
+		if err := writeAuditFile(*outputFile, report, write); err != nil {
+			log.Printf("Failed to write report: %v", err)
+			return 2
+		}
+		log.Printf("Audit report saved to: %s", *outputFile)
+	}
+
+	fmt.Fprintf(os.Stderr, "\nAudit Summary: %d pages, %d issues\n", report.Pages, len(report.Issues))
+	return 0
+}
+
+// writeAuditFile 将审计报告写入文件
+func writeAuditFile(path string, report audit.Report, write func(io.Writer, audit.Report) error) error {
+	if dir := filepath.Dir(path); dir != "." && dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return write(file, report)
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"flaremind/internal/checker"
+	"flaremind/internal/crawler"
+	"flaremind/internal/models"
+)
+
+// 链接检查模式的退出码
+const (
+	exitCheckPassed = 0 // 没有失效链接
+	exitCheckBroken = 1 // 存在失效链接
+	exitCheckError  = 2 // 参数错误或爬取失败
+)
+
+// runCheck 链接检查模式：爬取站点并验证所有发现的链接
+func runCheck(args []string) int {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	targetURL := fs.String("url", "", "URL to check (required)")
+	maxDepth := fs.Int("depth", 3, "Maximum crawl depth")
+	maxPages := fs.Int("pages", 100, "Maximum number of pages to crawl")
+	timeout := fs.Int("timeout", 60, "Timeout per page in seconds")
+	rateLimit := fs.Float64("rate", 2.0, "Maximum requests per second (0 = unlimited)")
+	delay := fs.Int("delay", 500, "Delay between requests in milliseconds")
+	external := fs.Bool("external", true, "Also check external links with HEAD (or a light GET)")
+	linkTimeout := fs.Int("link-timeout", 15, "Timeout per link check in seconds")
+	workers := fs.Int("workers", 8, "Number of concurrent link checks")
+	jsonFile := fs.String("json", "", "Write the report as JSON to this file")
+	junitFile := fs.String("junit", "", "Write the report as JUnit XML to this file")
+	fs.Parse(args)
+
+	if *targetURL == "" {
+		fmt.Fprintln(os.Stderr, "Error: URL is required")
+		fs.Usage()
+		return exitCheckError
+	}
+
+	parsedURL, err := parseURL(*targetURL)
+	if err != nil {
+		log.Printf("Invalid URL: %v", err)
+		return exitCheckError
+	}
+
+	log.Printf("Checking links on %s (depth: %d, pages: %d)", *targetURL, *maxDepth, *maxPages)
+
+	manager := newCrawlManager(*timeout, crawler.NewExtractor(), crawler.NewConverter())
+	config := models.CrawlConfig{
+		MaxDepth:            *maxDepth,
+		MaxPages:            *maxPages,
+		AllowedDomains:      []string{parsedURL.Host},
+		MaxWorkers:          5,
+		Timeout:             *timeout,
+		RateLimit:           *rateLimit,
+		Delay:               *delay,
+		RecordExternalLinks: *external,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(*timeout**maxPages+60)*time.Second)
+	defer cancel()
+
+	startTime := time.Now()
+	pages, err := manager.Crawl(ctx, *targetURL, config)
+	if err != nil {
+		log.Printf("Crawl failed: %v", err)
+		return exitCheckError
+	}
+
+	// 验证所有发现的链接
+	linkChecker := checker.NewChecker(time.Duration(*linkTimeout)*time.Second, *workers)
+	links := linkChecker.Check(context.Background(), pages, manager.LinkGraph().Edges(), manager.Report().FailedURLs)
+
+	report := checker.Report{
+		StartURL: *targetURL,
+		Checked:  len(links),
+		Broken:   checker.BrokenCount(links),
+		Duration: time.Since(startTime).String(),
+		Links:    links,
+	}
+
+	if *jsonFile != "" {
+		if err := writeReportFile(*jsonFile, report, checker.WriteJSON); err != nil {
+			log.Printf("Failed to write JSON report: %v", err)
+			return exitCheckError
+		}
+		log.Printf("JSON report saved to: %s", *jsonFile)
+	}
+	if *junitFile != "" {
+		if err := writeReportFile(*junitFile, report, checker.WriteJUnit); err != nil {
+			log.Printf("Failed to write JUnit report: %v", err)
+			return exitCheckError
+		}
+		log.Printf("JUnit report saved to: %s", *junitFile)
+	}
+
+	// 显示摘要信息
+	separator := strings.Repeat("-", 80) + "\n"
+	fmt.Fprint(os.Stderr, "\nLink Check Summary:\n")
+	fmt.Fprint(os.Stderr, separator)
+	fmt.Fprintf(os.Stderr, "Pages crawled: %d\n", len(pages))
+	fmt.Fprintf(os.Stderr, "Links checked: %d\n", report.Checked)
+	fmt.Fprintf(os.Stderr, "Broken links: %d\n", report.Broken)
+	for _, link := range links {
+		if !link.Broken {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "  %s\n", link.Summary())
+		for _, source := range link.Sources {
+			fmt.Fprintf(os.Stderr, "      linked from %s\n", source.Page)
+		}
+	}
+	fmt.Fprint(os.Stderr, separator)
+
+	if report.Broken > 0 {
+		return exitCheckBroken
+	}
+	return exitCheckPassed
+}
+
+// writeReportFile 将报告写入文件
+func writeReportFile(path string, report checker.Report, write func(io.Writer, checker.Report) error) error {
+	if dir := filepath.Dir(path); dir != "." && dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return write(file, report)
+}
@@ -1,23 +1,42 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"flaremind/internal/cache"
 	"flaremind/internal/crawler"
+	"flaremind/internal/graph"
 	"flaremind/internal/models"
+	"flaremind/internal/profile"
+	"flaremind/internal/schema"
+	"flaremind/pkg/utils"
+
+	"gopkg.in/yaml.v3"
 )
 
 func main() {
+	// 子命令
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "check":
+			os.Exit(runCheck(os.Args[2:]))
+		case "audit":
+			os.Exit(runAudit(os.Args[2:]))
+		}
+	}
+
 	var url string
 	var maxDepth int
 	var maxPages int
@@ -25,6 +44,34 @@ func main() {
 	var rateLimit float64
 	var delay int
 	var outputFile string
+	var nearDupThreshold float64
+	var dropNearDup bool
+	var boilerplateThreshold float64
+	var boilerplateMinPages int
+	var trapRepeat int
+	var trapURLLength int
+	var trapQueryVariants int
+	var trapTemplate int
+	var maxPerHost int
+	var budgetSpecs stringList
+	var followKinds string
+	var discoverOnclick bool
+	var respectNofollow bool
+	var respectMetaRobots bool
+	var respectXRobots bool
+	var graphFile string
+	var sortBy string
+	var sitemapURL string
+	var schemaFile string
+	var dataFile string
+	var profilesDir string
+	var builtinProfiles bool
+	var debugExtractDir string
+	var tableFallback string
+	var localLinks bool
+	var extractStrategy string
+	var extractRules stringList
+	var extractMinChars int
 
 	flag.StringVar(&url, "url", "https://go.dev/", "URL to crawl")
 	flag.IntVar(&maxDepth, "depth", 2, "Maximum crawl depth")
@@ -33,6 +80,34 @@ func main() {
 	flag.Float64Var(&rateLimit, "rate", 2.0, "Maximum requests per second (0 = unlimited)")
 	flag.IntVar(&delay, "delay", 500, "Delay between requests in milliseconds")
 	flag.StringVar(&outputFile, "o", "", "Output directory path (for multiple pages) or file path (for single page). If not specified, output JSON to stdout")
+	flag.Float64Var(&nearDupThreshold, "near-dup", 0, "Similarity threshold (0-1) for flagging near-duplicate pages by SimHash (0 = disabled)")
+	flag.BoolVar(&dropNearDup, "drop-near-dup", false, "Drop near-duplicate pages from results, keeping one page per group")
+	flag.Float64Var(&boilerplateThreshold, "boilerplate", 0, "Fraction (0-1) of a host's pages a content block must appear on to be removed as site boilerplate (0 = disabled)")
+	flag.IntVar(&boilerplateMinPages, "boilerplate-min-pages", crawler.DefaultBoilerplateMinPages, "Minimum pages crawled from a host before its boilerplate is learned")
+	flag.IntVar(&trapRepeat, "trap-repeat", 0, "Maximum occurrences of the same path segment in a URL (0 = unlimited)")
+	flag.IntVar(&trapURLLength, "trap-url-length", 0, "Maximum URL length (0 = unlimited)")
+	flag.IntVar(&trapQueryVariants, "trap-query-variants", 0, "Maximum distinct query strings per path (0 = unlimited)")
+	flag.IntVar(&trapTemplate, "trap-template", 0, "Maximum URLs per path template with IDs and dates collapsed (0 = unlimited)")
+	flag.IntVar(&maxPerHost, "max-per-host", 0, "Maximum pages enqueued per host (0 = unlimited)")
+	flag.Var(&budgetSpecs, "budget", "Page budget rule, e.g. \"prefix=/blog/,max=200\" or \"depth=2,per-host,max=50\" (repeatable)")
+	flag.StringVar(&followKinds, "follow", "", "Comma-separated link kinds to follow: anchor,next,prev,alternate,area,iframe,frame,refresh,data-href,onclick (empty = all)")
+	flag.BoolVar(&discoverOnclick, "onclick", false, "Discover links from onclick=\"location.href=...\" handlers")
+	flag.BoolVar(&respectNofollow, "respect-nofollow", true, "Do not follow links marked rel=nofollow, ugc or sponsored")
+	flag.BoolVar(&respectMetaRobots, "respect-meta-robots", true, "Honor noindex/nofollow in <meta name=\"robots\">")
+	flag.BoolVar(&respectXRobots, "respect-x-robots", true, "Honor noindex/nofollow in the X-Robots-Tag header")
+	flag.StringVar(&graphFile, "graph", "", "Export the link graph to this file; format by extension: .json, .dot/.gv (GraphViz) or .gexf")
+	flag.StringVar(&sortBy, "sort", "crawl", "Order of pages in the output: crawl, rank (PageRank), inlinks, depth, url or nav (docs sidebar order)")
+	flag.StringVar(&sitemapURL, "sitemap", "", "Sitemap URL whose pages are added to the queue at depth 1")
+	flag.StringVar(&schemaFile, "schema", "", "Field extraction schema (.yaml, .yml or .json); results are stored in each page's data")
+	flag.StringVar(&profilesDir, "profiles", "", "Directory of per-site extraction profiles (.yaml/.yml), matched before the built-in profiles")
+	flag.BoolVar(&builtinProfiles, "builtin-profiles", true, "Use the built-in extraction profiles for common platforms (WordPress, MediaWiki, Discourse, ...)")
+	flag.StringVar(&extractStrategy, "extract", "profile,auto", "Content extraction strategy, or a comma-separated fallback chain: auto, readability, heuristic, body, profile, raw")
+	flag.Var(&extractRules, "extract-rule", "Per-URL extraction strategy as PATTERN=STRATEGY[,STRATEGY...] (regexp on the URL, repeatable, first match wins)")
+	flag.IntVar(&extractMinChars, "extract-min-chars", 0, "Fall back to the next strategy in a chain when the content has fewer characters (0 = only when empty)")
+	flag.BoolVar(&localLinks, "local-links", false, "In directory output, rewrite links between crawled pages to the local Markdown files")
+	flag.StringVar(&tableFallback, "table-fallback", crawler.TableFallbackHTML, "Output for tables GFM cannot represent (nested tables, multi-paragraph cells): html (passthrough) or list")
+	flag.StringVar(&debugExtractDir, "debug-extract", "", "Directory to write per-page extraction debug output (candidate scores as JSON and annotated HTML)")
+	flag.StringVar(&dataFile, "data", "", "Export extracted records to this file; format by extension: .jsonl or .csv (requires -schema)")
 	flag.Parse()
 
 	if url == "" {
@@ -49,26 +124,108 @@ func main() {
 	log.Printf("Rate Limit: %.2f requests/second", rateLimit)
 	log.Printf("Delay: %d ms between requests", delay)
 
-	// 初始化组件
-	renderer := crawler.NewRenderer(time.Duration(timeout)*time.Second, true)
+	// 解析 URL 获取域名
+	parsedURL, err := parseURL(url)
+	if err != nil {
+		log.Fatalf("Invalid URL: %v", err)
+	}
+
+	// 解析页面预算
+	var budgets []models.PageBudget
+	if maxPerHost > 0 {
+		budgets = append(budgets, models.PageBudget{PerHost: true, MaxPages: maxPerHost})
+	}
+	for _, spec := range budgetSpecs {
+		budget, err := crawler.ParseBudget(spec)
+		if err != nil {
+			log.Fatalf("Invalid budget: %v", err)
+		}
+		budgets = append(budgets, budget)
+	}
+
+	// 解析跟随的链接类型
+	linkKinds, err := crawler.ParseLinkKinds(followKinds)
+	if err != nil {
+		log.Fatalf("Invalid -follow: %v", err)
+	}
+
+	// 检查排序方式
+	if err := crawler.ValidateSortOrder(sortBy); err != nil {
+		log.Fatalf("Invalid -sort: %v", err)
+	}
+
+	// 加载字段提取配置
+	var extractionSchema *schema.Schema
+	if schemaFile != "" {
+		extractionSchema, err = schema.Load(schemaFile)
+		if err != nil {
+			log.Fatalf("Invalid -schema: %v", err)
+		}
+	} else if dataFile != "" {
+		log.Fatalf("-data requires -schema")
+	}
+
+	// 加载站点提取配置（用户配置优先于内置配置）
+	var siteProfiles []*profile.Profile
+	if profilesDir != "" {
+		loaded, err := profile.LoadDir(profilesDir)
+		if err != nil {
+			log.Fatalf("Invalid -profiles: %v", err)
+		}
+		log.Printf("Loaded %d extraction profiles from %s", len(loaded), profilesDir)
+		siteProfiles = append(siteProfiles, loaded...)
+	}
+	if builtinProfiles {
+		siteProfiles = append(siteProfiles, profile.Builtin()...)
+	}
+	var profileSet *profile.Set
+	if len(siteProfiles) > 0 {
+		profileSet = profile.NewSet(siteProfiles...)
+	}
+
+	// 解析正文提取策略（按 URL 的规则优先于默认策略）
 	extractor := crawler.NewExtractor()
+	contentExtractor, err := crawler.ParseStrategy(extractStrategy, extractor, profileSet, extractMinChars)
+	if err != nil {
+		log.Fatalf("Invalid -extract: %v", err)
+	}
+	if len(extractRules) > 0 {
+		router := &crawler.RouteExtractor{Default: contentExtractor}
+		for _, spec := range extractRules {
+			rule, err := crawler.ParseExtractionRule(spec, extractor, profileSet, extractMinChars)
+			if err != nil {
+				log.Fatalf("Invalid -extract-rule: %v", err)
+			}
+			router.Rules = append(router.Rules, rule)
+		}
+		contentExtractor = router
+	}
+
+	// 创建 Markdown 转换器
 	converter := crawler.NewConverter()
-	cacheInstance := cache.NewCache(24*time.Hour, 1*time.Hour)
+	if err := converter.SetTableFallback(tableFallback); err != nil {
+		log.Fatalf("Invalid -table-fallback: %v", err)
+	}
 
 	// 创建爬取管理器
-	manager := crawler.NewCrawlManager(
-		renderer,
-		extractor,
-		converter,
-		cacheInstance,
-		5,                                  // maxWorkers
-		time.Duration(timeout)*time.Second, // timeout
-	)
+	manager := newCrawlManager(timeout, contentExtractor, converter)
+	if extractionSchema != nil {
+		manager.SetSchema(extractionSchema)
+	}
+	if profileSet != nil {
+		manager.SetProfiles(profileSet)
+	}
 
-	// 解析 URL 获取域名
-	parsedURL, err := parseURL(url)
-	if err != nil {
-		log.Fatalf("Invalid URL: %v", err)
+	// 输出正文提取调试信息
+	if debugExtractDir != "" {
+		if err := os.MkdirAll(debugExtractDir, 0755); err != nil {
+			log.Fatalf("Failed to create -debug-extract directory: %v", err)
+		}
+		manager.SetExtractionDebug(func(debug *crawler.ExtractionDebug) {
+			if err := writeExtractionDebug(debugExtractDir, debug); err != nil {
+				log.Printf("Failed to write extraction debug for %s: %v", debug.URL, err)
+			}
+		})
 	}
 
 	// 创建配置
@@ -80,6 +237,30 @@ func main() {
 		Timeout:        timeout,
 		RateLimit:      rateLimit,
 		Delay:          delay,
+
+		NearDuplicateThreshold: nearDupThreshold,
+		DropNearDuplicates:     dropNearDup,
+
+		BoilerplateThreshold: boilerplateThreshold,
+		BoilerplateMinPages:  boilerplateMinPages,
+
+		Traps: models.TrapConfig{
+			MaxRepeatedSegments: trapRepeat,
+			MaxURLLength:        trapURLLength,
+			MaxQueryVariants:    trapQueryVariants,
+			MaxURLsPerTemplate:  trapTemplate,
+		},
+
+		Budgets: budgets,
+
+		FollowLinkKinds:      linkKinds,
+		DiscoverOnclickLinks: discoverOnclick,
+
+		RespectNofollowLinks: respectNofollow,
+		RespectMetaRobots:    respectMetaRobots,
+		RespectXRobotsTag:    respectXRobots,
+
+		SitemapURL: sitemapURL,
 	}
 
 	// 创建上下文
@@ -94,11 +275,17 @@ func main() {
 	startTime := time.Now()
 	pages, err := manager.Crawl(ctx, url, config)
 	duration := time.Since(startTime)
+	report := manager.Report()
 
 	if err != nil {
 		log.Fatalf("Crawl failed: %v", err)
 	}
 
+	// 按指定方式排序
+	if err := crawler.SortResults(pages, sortBy); err != nil {
+		log.Fatalf("Failed to sort results: %v", err)
+	}
+
 	// 输出结果
 	log.Println("=" + strings.Repeat("=", 60) + "=")
 	log.Printf("Crawl completed in %v", duration)
@@ -117,10 +304,18 @@ func main() {
 
 	// 如果指定了输出路径，保存为 Markdown 格式
 	if outputFile != "" {
+		// 重复页面（与其他页面共享规范 URL）不单独保存
+		uniquePages := make([]models.PageResult, 0, len(pages))
+		for _, page := range pages {
+			if page.DuplicateOf == "" {
+				uniquePages = append(uniquePages, page)
+			}
+		}
+
 		// 判断是目录还是文件
 		// 如果只有一个页面且路径以 .md 结尾，保存为单个文件
 		// 否则作为目录，每个页面保存为独立文件
-		isSingleFile := len(pages) == 1 && strings.HasSuffix(strings.ToLower(outputFile), ".md")
+		isSingleFile := len(uniquePages) == 1 && strings.HasSuffix(strings.ToLower(outputFile), ".md")
 
 		if isSingleFile {
 			// 单个文件模式（向后兼容）
@@ -137,13 +332,7 @@ func main() {
 			}
 			defer file.Close()
 
-			page := pages[0]
-			file.WriteString(fmt.Sprintf("# %s\n\n", page.URL))
-			file.WriteString(fmt.Sprintf("**Source URL:** %s  \n", page.URL))
-			file.WriteString(fmt.Sprintf("**Depth:** %d  \n\n", page.Depth))
-			file.WriteString("---\n\n")
-			file.WriteString(page.Markdown)
-			file.WriteString("\n")
+			writePageMarkdown(file, uniquePages[0])
 
 			log.Printf("Results saved to: %s (Markdown format)", outputFile)
 		} else {
@@ -159,12 +348,23 @@ func main() {
 				log.Fatalf("Failed to create output directory: %v", err)
 			}
 
+			// 生成安全的文件名
+			filenames := make([]string, len(uniquePages))
+			for i, page := range uniquePages {
+				filenames[i] = sanitizeFilename(page.URL, i)
+			}
+			var rewriteLink func(string) (string, bool)
+			if localLinks {
+				rewriteLink = localLinkRewriter(pages, uniquePages, filenames)
+			}
+
 			// 为每个页面创建文件
 			savedCount := 0
-			for i, page := range pages {
-				// 生成安全的文件名
-				filename := sanitizeFilename(page.URL, i)
-				filePath := filepath.Join(outputDir, filename)
+			for i, page := range uniquePages {
+				filePath := filepath.Join(outputDir, filenames[i])
+				if rewriteLink != nil {
+					page.Markdown = crawler.RewriteMarkdownLinks(page.Markdown, rewriteLink)
+				}
 
 				file, err := os.Create(filePath)
 				if err != nil {
@@ -173,12 +373,7 @@ func main() {
 				}
 
 				// 写入 Markdown 内容
-				file.WriteString(fmt.Sprintf("# %s\n\n", page.URL))
-				file.WriteString(fmt.Sprintf("**Source URL:** %s  \n", page.URL))
-				file.WriteString(fmt.Sprintf("**Depth:** %d  \n\n", page.Depth))
-				file.WriteString("---\n\n")
-				file.WriteString(page.Markdown)
-				file.WriteString("\n")
+				writePageMarkdown(file, page)
 
 				file.Close()
 				savedCount++
@@ -193,6 +388,7 @@ func main() {
 			"total":    len(pages),
 			"duration": duration.String(),
 			"pages":    pages,
+			"report":   report,
 		}
 		encoder := json.NewEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
@@ -201,6 +397,22 @@ func main() {
 		}
 	}
 
+	// 导出链接图
+	if graphFile != "" {
+		if err := writeLinkGraph(graphFile, manager.LinkGraph()); err != nil {
+			log.Fatalf("Failed to export link graph: %v", err)
+		}
+		log.Printf("Link graph saved to: %s", graphFile)
+	}
+
+	// 导出提取的记录
+	if dataFile != "" {
+		if err := writeExtractedData(dataFile, extractionSchema, pages); err != nil {
+			log.Fatalf("Failed to export extracted data: %v", err)
+		}
+		log.Printf("Extracted data saved to: %s", dataFile)
+	}
+
 	// 显示摘要信息
 	fmt.Fprint(os.Stderr, "\nCrawl Summary:\n")
 	separator := strings.Repeat("-", 80) + "\n"
@@ -208,11 +420,209 @@ func main() {
 	fmt.Fprintf(os.Stderr, "Total pages: %d\n", len(pages))
 	fmt.Fprintf(os.Stderr, "Duration: %v\n", duration)
 	for i, page := range pages {
+		if page.DuplicateOf != "" {
+			fmt.Fprintf(os.Stderr, "[%d] %s (depth: %d, duplicate of: %s)\n", i+1, page.URL, page.Depth, page.DuplicateOf)
+			continue
+		}
+		if page.NearDuplicateOf != "" {
+			fmt.Fprintf(os.Stderr, "[%d] %s (depth: %d, near-duplicate group %d of: %s)\n", i+1, page.URL, page.Depth, page.NearDuplicateGroup, page.NearDuplicateOf)
+			continue
+		}
 		fmt.Fprintf(os.Stderr, "[%d] %s (depth: %d)\n", i+1, page.URL, page.Depth)
 	}
+	if len(report.TrappedURLs) > 0 {
+		fmt.Fprintf(os.Stderr, "Trapped URLs: %d\n", len(report.TrappedURLs))
+		for _, trapped := range report.TrappedURLs {
+			fmt.Fprintf(os.Stderr, "  [%s] %s\n", trapped.Rule, trapped.URL)
+		}
+	}
+	if len(report.NoIndexURLs) > 0 {
+		fmt.Fprintf(os.Stderr, "Noindex pages (crawled for links only): %d\n", len(report.NoIndexURLs))
+	}
+	budgetKeys := make([]string, 0, len(report.BudgetExhausted))
+	for budget := range report.BudgetExhausted {
+		budgetKeys = append(budgetKeys, budget)
+	}
+	sort.Strings(budgetKeys)
+	for _, budget := range budgetKeys {
+		fmt.Fprintf(os.Stderr, "Budget exhausted: %s (%d URLs skipped)\n", budget, report.BudgetExhausted[budget])
+	}
+	if len(report.ExtractionErrors) > 0 {
+		fmt.Fprintf(os.Stderr, "Extraction errors: %d\n", len(report.ExtractionErrors))
+		for _, extractionErr := range report.ExtractionErrors {
+			fmt.Fprintf(os.Stderr, "  %s: field %q: %s\n", extractionErr.URL, extractionErr.Field, extractionErr.Error)
+		}
+	}
 	fmt.Fprint(os.Stderr, separator)
 }
 
+// newCrawlManager 初始化组件并创建使用指定正文提取策略和 Markdown 转换器的爬取管理器
+func newCrawlManager(timeout int, extractor crawler.ContentExtractor, converter *crawler.Converter) *crawler.CrawlManager {
+	renderer := crawler.NewRenderer(time.Duration(timeout)*time.Second, true)
+	cacheInstance := cache.NewCache(24*time.Hour, 1*time.Hour)
+
+	return crawler.NewCrawlManager(
+		renderer,
+		extractor,
+		converter,
+		cacheInstance,
+		5,                                  // maxWorkers
+		time.Duration(timeout)*time.Second, // timeout
+	)
+}
+
+// pageFrontMatter Markdown 文件开头的 YAML front matter
+type pageFrontMatter struct {
+	Title               string `yaml:"title,omitempty"`
+	Description         string `yaml:"description,omitempty"`
+	URL                 string `yaml:"url"`
+	Canonical           string `yaml:"canonical,omitempty"`
+	Depth               int    `yaml:"depth"`
+	models.PageMetadata `yaml:",inline"`
+}
+
+// writePageMarkdown 写入单个页面的 Markdown 内容（YAML front matter + 标题 + 正文）
+func writePageMarkdown(w io.Writer, page models.PageResult) {
+	frontMatter := pageFrontMatter{
+		Title:       page.Title,
+		Description: page.Description,
+		URL:         page.URL,
+		Canonical:   page.Canonical,
+		Depth:       page.Depth,
+	}
+	if page.Metadata != nil {
+		frontMatter.PageMetadata = *page.Metadata
+	}
+	var data bytes.Buffer
+	encoder := yaml.NewEncoder(&data)
+	encoder.SetIndent(2)
+	err := encoder.Encode(frontMatter)
+	if err == nil {
+		err = encoder.Close()
+	}
+	if err == nil {
+		fmt.Fprintf(w, "---\n%s---\n\n", data.String())
+	}
+
+	title := page.Title
+	if title == "" {
+		title = page.URL
+	}
+	fmt.Fprintf(w, "# %s\n\n", title)
+	fmt.Fprint(w, page.Markdown)
+	fmt.Fprint(w, "\n")
+}
+
+// localLinkRewriter 返回将已爬取页面之间的链接改写为本地 Markdown 文件名的函数
+// 页面的 URL 和规范 URL 都指向该页面的文件，重复页面指向其保留页面的文件
+func localLinkRewriter(pages []models.PageResult, uniquePages []models.PageResult, filenames []string) func(string) (string, bool) {
+	files := make(map[string]string)
+	addFile := func(pageURL, filename string) {
+		if normalized, err := utils.NormalizeURL(pageURL); err == nil {
+			if _, exists := files[normalized]; !exists {
+				files[normalized] = filename
+			}
+		}
+	}
+	for i, page := range uniquePages {
+		addFile(page.URL, filenames[i])
+		if page.Canonical != "" {
+			addFile(page.Canonical, filenames[i])
+		}
+	}
+	for _, page := range pages {
+		if page.DuplicateOf == "" {
+			continue
+		}
+		if normalized, err := utils.NormalizeURL(page.DuplicateOf); err == nil {
+			if filename, ok := files[normalized]; ok {
+				addFile(page.URL, filename)
+			}
+		}
+	}
+
+	return func(dest string) (string, bool) {
+		fragment := ""
+		if i := strings.Index(dest, "#"); i >= 0 {
+			dest, fragment = dest[:i], dest[i:]
+		}
+		normalized, err := utils.NormalizeURL(dest)
+		if err != nil {
+			return "", false
+		}
+		filename, ok := files[normalized]
+		if !ok {
+			return "", false
+		}
+		return filename + fragment, true
+	}
+}
+
+// writeLinkGraph 按文件扩展名导出链接图
+func writeLinkGraph(path string, linkGraph *graph.Graph) error {
+	if dir := filepath.Dir(path); dir != "." && dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return linkGraph.Write(file, graph.FormatFromPath(path))
+}
+
+// writeExtractedData 按文件扩展名导出字段提取结果
+func writeExtractedData(path string, extractionSchema *schema.Schema, pages []models.PageResult) error {
+	if dir := filepath.Dir(path); dir != "." && dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return extractionSchema.Write(file, pages, schema.FormatFromPath(path))
+}
+
+// writeExtractionDebug 将页面的正文提取调试信息写入目录：<页面文件名>.extract.json 和 <页面文件名>.extract.html
+func writeExtractionDebug(dir string, debug *crawler.ExtractionDebug) error {
+	base := filepath.Join(dir, strings.TrimSuffix(sanitizeFilename(debug.URL, 0), ".md")+".extract")
+
+	data, err := json.MarshalIndent(debug, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(base+".json", data, 0644); err != nil {
+		return err
+	}
+
+	annotated, err := debug.AnnotatedHTML()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(base+".html", []byte(annotated), 0644)
+}
+
+// stringList 可重复指定的字符串参数
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ";")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
 func parseURL(rawURL string) (*url.URL, error) {
 	return url.Parse(rawURL)
 }
@@ -0,0 +1,170 @@
+package audit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"flaremind/internal/models"
+)
+
+// 问题类型
+const (
+	IssueMissingTitle         = "missing-title"
+	IssueDuplicateTitle       = "duplicate-title"
+	IssueMissingDescription   = "missing-description"
+	IssueDuplicateDescription = "duplicate-description"
+	IssueMissingH1            = "missing-h1"
+	IssueMultipleH1           = "multiple-h1"
+	IssueRedirectChain        = "redirect-chain"
+	IssueThinContent          = "thin-content"
+	IssueOrphanPage           = "orphan-page"
+	IssueDeepPage             = "deep-page"
+)
+
+// issueTitles 问题类型的可读标题（同时决定报告中的顺序）
+var issueTitles = []struct {
+	Type  string
+	Title string
+}{
+	{IssueMissingTitle, "Missing <title>"},
+	{IssueDuplicateTitle, "Duplicate <title>"},
+	{IssueMissingDescription, "Missing meta description"},
+	{IssueDuplicateDescription, "Duplicate meta description"},
+	{IssueMissingH1, "Missing H1"},
+	{IssueMultipleH1, "Multiple H1"},
+	{IssueRedirectChain, "Redirect chains longer than one hop"},
+	{IssueThinContent, "Thin content"},
+	{IssueOrphanPage, "Orphan pages (only found through the sitemap)"},
+	{IssueDeepPage, "Deep pages"},
+}
+
+// Config 审计配置
+type Config struct {
+	MinWords      int // 正文少于该词数视为内容过少
+	MaxClickDepth int // 点击深度超过该值视为过深
+}
+
+// Issue 审计发现的问题（重复类问题的 URLs 包含同组的所有页面）
+type Issue struct {
+	Type   string   `json:"type"`
+	URLs   []string `json:"urls"`
+	Detail string   `json:"detail,omitempty"`
+}
+
+// Report 审计报告
+type Report struct {
+	StartURL string         `json:"start_url"`
+	Pages    int            `json:"pages"`
+	Config   Config         `json:"config"`
+	Counts   map[string]int `json:"counts"`
+	Issues   []Issue        `json:"issues"`
+}
+
+// Audit 对爬取结果进行站点审计
+func Audit(startURL string, pages []models.PageResult, config Config) Report {
+	report := Report{
+		StartURL: startURL,
+		Config:   config,
+		Counts:   make(map[string]int),
+	}
+
+	titles := make(map[string][]string)
+	descriptions := make(map[string][]string)
+
+	for _, page := range pages {
+		// 重复页面（共享规范 URL）不参与审计
+		if page.DuplicateOf != "" {
+			continue
+		}
+		report.Pages++
+
+		if page.Title == "" {
+			report.add(Issue{Type: IssueMissingTitle, URLs: []string{page.URL}})
+		} else {
+			titles[page.Title] = append(titles[page.Title], page.URL)
+		}
+
+		if page.Description == "" {
+			report.add(Issue{Type: IssueMissingDescription, URLs: []string{page.URL}})
+		} else {
+			descriptions[page.Description] = append(descriptions[page.Description], page.URL)
+		}
+
+		switch {
+		case len(page.H1) == 0:
+			report.add(Issue{Type: IssueMissingH1, URLs: []string{page.URL}})
+		case len(page.H1) > 1:
+			report.add(Issue{Type: IssueMultipleH1, URLs: []string{page.URL}, Detail: strings.Join(page.H1, " | ")})
+		}
+
+		if len(page.RedirectChain) > 1 {
+			finalURL := page.FinalURL
+			if finalURL == "" {
+				finalURL = page.URL
+			}
+			chain := append(append([]string(nil), page.RedirectChain...), finalURL)
+			report.add(Issue{
+				Type:   IssueRedirectChain,
+				URLs:   []string{page.URL},
+				Detail: fmt.Sprintf("%d hops: %s", len(page.RedirectChain), strings.Join(chain, " -> ")),
+			})
+		}
+
+		if config.MinWords > 0 && page.WordCount < config.MinWords {
+			report.add(Issue{Type: IssueThinContent, URLs: []string{page.URL}, Detail: fmt.Sprintf("%d words", page.WordCount)})
+		}
+
+		if page.InSitemap && page.InLinks == 0 && len(page.DiscoveryPath) == 0 {
+			report.add(Issue{Type: IssueOrphanPage, URLs: []string{page.URL}})
+		}
+
+		if clicks := len(page.DiscoveryPath) - 1; config.MaxClickDepth > 0 && clicks > config.MaxClickDepth {
+			report.add(Issue{Type: IssueDeepPage, URLs: []string{page.URL}, Detail: fmt.Sprintf("%d clicks", clicks)})
+		}
+	}
+
+	for _, group := range duplicateGroups(titles) {
+		report.add(Issue{Type: IssueDuplicateTitle, URLs: group.urls, Detail: group.value})
+	}
+	for _, group := range duplicateGroups(descriptions) {
+		report.add(Issue{Type: IssueDuplicateDescription, URLs: group.urls, Detail: group.value})
+	}
+
+	// 按问题类型排序，同类问题保持发现顺序
+	order := make(map[string]int)
+	for i, item := range issueTitles {
+		order[item.Type] = i
+	}
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		return order[report.Issues[i].Type] < order[report.Issues[j].Type]
+	})
+
+	return report
+}
+
+// add 添加问题并更新计数
+func (r *Report) add(issue Issue) {
+	r.Issues = append(r.Issues, issue)
+	r.Counts[issue.Type]++
+}
+
+// duplicateGroup 共享同一个值的页面
+type duplicateGroup struct {
+	value string
+	urls  []string
+}
+
+// duplicateGroups 返回出现在多个页面上的值（按值排序）
+func duplicateGroups(values map[string][]string) []duplicateGroup {
+	var groups []duplicateGroup
+	for value, urls := range values {
+		if len(urls) > 1 {
+			groups = append(groups, duplicateGroup{value: value, urls: urls})
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].value < groups[j].value
+	})
+	return groups
+}
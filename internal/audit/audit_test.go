@@ -0,0 +1,72 @@
+package audit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"flaremind/internal/models"
+)
+
+func TestAudit(t *testing.T) {
+	root := "https://example.com/"
+	pages := []models.PageResult{
+		{URL: root, Title: "Home", Description: "Welcome", H1: []string{"Home"}, WordCount: 500, DiscoveryPath: []string{root}},
+		{URL: "https://example.com/a", Title: "Docs", Description: "Docs", H1: []string{"A", "B"}, WordCount: 20,
+			DiscoveryPath: []string{root, "https://example.com/a"}, InLinks: 1},
+		{URL: "https://example.com/b", Title: "Docs", WordCount: 800,
+			RedirectChain: []string{"https://example.com/b", "https://example.com/b/"}, FinalURL: "https://example.com/docs/b/",
+			DiscoveryPath: []string{root, "https://example.com/a", "https://example.com/x", "https://example.com/b"}, InLinks: 1},
+		{URL: "https://example.com/orphan", Title: "Orphan", Description: "Alone", H1: []string{"Orphan"}, WordCount: 300, InSitemap: true},
+		{URL: "https://example.com/?ref=1", DuplicateOf: root},
+	}
+
+	report := Audit(root, pages, Config{MinWords: 100, MaxClickDepth: 2})
+
+	if report.Pages != 4 {
+		t.Errorf("Expected duplicates to be skipped, audited %d pages", report.Pages)
+	}
+
+	expected := map[string]int{
+		IssueDuplicateTitle:       1,
+		IssueMissingDescription:   1,
+		IssueMissingH1:            1,
+		IssueMultipleH1:           1,
+		IssueRedirectChain:        1,
+		IssueThinContent:          1,
+		IssueOrphanPage:           1,
+		IssueDeepPage:             1,
+		IssueMissingTitle:         0,
+		IssueDuplicateDescription: 0,
+	}
+	for issueType, count := range expected {
+		if report.Counts[issueType] != count {
+			t.Errorf("Expected %d %s issues, got %d", count, issueType, report.Counts[issueType])
+		}
+	}
+
+	duplicates := report.issuesOfType(IssueDuplicateTitle)
+	if len(duplicates) != 1 || len(duplicates[0].URLs) != 2 || duplicates[0].Detail != "Docs" {
+		t.Errorf("Unexpected duplicate title issue: %+v", duplicates)
+	}
+
+	redirects := report.issuesOfType(IssueRedirectChain)
+	wantChain := "2 hops: https://example.com/b -> https://example.com/b/ -> https://example.com/docs/b/"
+	if len(redirects) != 1 || redirects[0].Detail != wantChain {
+		t.Errorf("Unexpected redirect chain issue: %+v", redirects)
+	}
+
+	var markdown, html bytes.Buffer
+	if err := WriteMarkdown(&markdown, report); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	if !strings.Contains(markdown.String(), "## Redirect chains longer than one hop") {
+		t.Errorf("Expected Markdown report to contain redirect section, got: %s", markdown.String())
+	}
+	if err := WriteHTML(&html, report); err != nil {
+		t.Fatalf("WriteHTML() error = %v", err)
+	}
+	if !strings.Contains(html.String(), "&lt;title&gt;") {
+		t.Error("Expected HTML report to escape check titles")
+	}
+}
@@ -0,0 +1,118 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// WriteJSON 以 JSON 格式写入审计报告
+func WriteJSON(w io.Writer, report Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// WriteMarkdown 以 Markdown 格式写入审计报告
+func WriteMarkdown(w io.Writer, report Report) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Site Audit: %s\n\n", report.StartURL)
+	fmt.Fprintf(&b, "**Pages audited:** %d  \n", report.Pages)
+	fmt.Fprintf(&b, "**Issues found:** %d\n\n", len(report.Issues))
+
+	b.WriteString("| Check | Issues |\n| --- | --- |\n")
+	for _, item := range issueTitles {
+		fmt.Fprintf(&b, "| %s | %d |\n", item.Title, report.Counts[item.Type])
+	}
+
+	for _, item := range issueTitles {
+		issues := report.issuesOfType(item.Type)
+		if len(issues) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", item.Title)
+		for _, issue := range issues {
+			if len(issue.URLs) == 1 {
+				fmt.Fprintf(&b, "- <%s>", issue.URLs[0])
+				if issue.Detail != "" {
+					fmt.Fprintf(&b, " — %s", issue.Detail)
+				}
+				b.WriteString("\n")
+				continue
+			}
+			fmt.Fprintf(&b, "- %q shared by %d pages:\n", issue.Detail, len(issue.URLs))
+			for _, url := range issue.URLs {
+				fmt.Fprintf(&b, "  - <%s>\n", url)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteHTML 以 HTML 格式写入审计报告
+func WriteHTML(w io.Writer, report Report) error {
+	var b strings.Builder
+	e := html.EscapeString
+
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&b, "<title>Site Audit: %s</title>\n", e(report.StartURL))
+	b.WriteString("<style>body{font-family:sans-serif;max-width:960px;margin:2em auto}table{border-collapse:collapse}td,th{border:1px solid #ccc;padding:4px 8px}.ok{color:#2a7}.bad{color:#c33}</style>\n")
+	b.WriteString("</head>\n<body>\n")
+	fmt.Fprintf(&b, "<h1>Site Audit: %s</h1>\n", e(report.StartURL))
+	fmt.Fprintf(&b, "<p>Pages audited: %d. Issues found: %d.</p>\n", report.Pages, len(report.Issues))
+
+	b.WriteString("<table>\n<tr><th>Check</th><th>Issues</th></tr>\n")
+	for _, item := range issueTitles {
+		class := "ok"
+		if report.Counts[item.Type] > 0 {
+			class = "bad"
+		}
+		fmt.Fprintf(&b, "<tr><td>%s</td><td class=\"%s\">%d</td></tr>\n", e(item.Title), class, report.Counts[item.Type])
+	}
+	b.WriteString("</table>\n")
+
+	for _, item := range issueTitles {
+		issues := report.issuesOfType(item.Type)
+		if len(issues) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "<h2>%s</h2>\n<ul>\n", e(item.Title))
+		for _, issue := range issues {
+			if len(issue.URLs) == 1 {
+				fmt.Fprintf(&b, "<li><a href=\"%s\">%s</a>", e(issue.URLs[0]), e(issue.URLs[0]))
+				if issue.Detail != "" {
+					fmt.Fprintf(&b, " — %s", e(issue.Detail))
+				}
+				b.WriteString("</li>\n")
+				continue
+			}
+			fmt.Fprintf(&b, "<li>&ldquo;%s&rdquo; shared by %d pages<ul>\n", e(issue.Detail), len(issue.URLs))
+			for _, url := range issue.URLs {
+				fmt.Fprintf(&b, "<li><a href=\"%s\">%s</a></li>\n", e(url), e(url))
+			}
+			b.WriteString("</ul></li>\n")
+		}
+		b.WriteString("</ul>\n")
+	}
+
+	b.WriteString("</body>\n</html>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// issuesOfType 返回指定类型的问题
+func (r Report) issuesOfType(issueType string) []Issue {
+	var issues []Issue
+	for _, issue := range r.Issues {
+		if issue.Type == issueType {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
@@ -0,0 +1,255 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"flaremind/internal/crawler"
+	"flaremind/internal/models"
+)
+
+// 链接错误类型
+const (
+	ErrorKindHTTP    = "http"    // 4xx/5xx 状态码
+	ErrorKindDNS     = "dns"     // 域名解析失败
+	ErrorKindTimeout = "timeout" // 请求超时
+	ErrorKindNetwork = "network" // 其他网络错误
+	ErrorKindRender  = "render"  // 爬取时页面渲染失败
+)
+
+// LinkSource 包含某个链接的页面
+type LinkSource struct {
+	Page string          `json:"page"`
+	Text string          `json:"text,omitempty"`
+	Kind models.LinkKind `json:"kind"`
+}
+
+// LinkStatus 单个链接的检查结果
+type LinkStatus struct {
+	URL        string       `json:"url"`
+	External   bool         `json:"external"`
+	StatusCode int          `json:"status_code,omitempty"`
+	Method     string       `json:"method"` // crawl（爬取时已验证）、HEAD 或 GET
+	Broken     bool         `json:"broken"`
+	ErrorKind  string       `json:"error_kind,omitempty"`
+	Error      string       `json:"error,omitempty"`
+	Sources    []LinkSource `json:"sources"`
+}
+
+// Report 链接检查报告
+type Report struct {
+	StartURL string       `json:"start_url"`
+	Checked  int          `json:"checked"`
+	Broken   int          `json:"broken"`
+	Duration string       `json:"duration"`
+	Links    []LinkStatus `json:"links"`
+}
+
+// Checker 链接检查器
+type Checker struct {
+	client      *http.Client
+	workers     int
+	retryConfig crawler.RetryConfig
+}
+
+// NewChecker 创建新的链接检查器
+func NewChecker(timeout time.Duration, workers int) *Checker {
+	if workers <= 0 {
+		workers = 1
+	}
+	return &Checker{
+		client:  &http.Client{Timeout: timeout},
+		workers: workers,
+		retryConfig: crawler.RetryConfig{
+			MaxRetries:        1,
+			InitialDelay:      500 * time.Millisecond,
+			MaxDelay:          2 * time.Second,
+			BackoffMultiplier: 2.0,
+		},
+	}
+}
+
+// Check 检查链接图中的所有链接
+// 已爬取页面直接使用爬取时的状态码和渲染错误，其余链接通过 HEAD（必要时降级为 GET）验证
+func (c *Checker) Check(ctx context.Context, pages []models.PageResult, edges []models.LinkEdge, failures []models.FailedURL) []LinkStatus {
+	crawled := make(map[string]int)
+	for _, page := range pages {
+		if page.StatusCode > 0 {
+			crawled[page.URL] = page.StatusCode
+		}
+	}
+	failed := make(map[string]string)
+	for _, failure := range failures {
+		failed[failure.URL] = failure.Error
+	}
+
+	// 按目标 URL 汇总来源页面
+	statuses := make(map[string]*LinkStatus)
+	var targets []string
+	for _, edge := range edges {
+		status, ok := statuses[edge.Target]
+		if !ok {
+			status = &LinkStatus{URL: edge.Target, External: edge.External}
+			statuses[edge.Target] = status
+			targets = append(targets, edge.Target)
+		}
+		status.Sources = append(status.Sources, LinkSource{Page: edge.Source, Text: edge.Text, Kind: edge.Kind})
+	}
+
+	jobs := make(chan *LinkStatus)
+	var wg sync.WaitGroup
+	for i := 0; i < c.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for status := range jobs {
+				c.checkLink(ctx, status)
+			}
+		}()
+	}
+
+	for _, target := range targets {
+		status := statuses[target]
+		if code, ok := crawled[target]; ok {
+			status.Method = "crawl"
+			status.StatusCode = code
+			status.Broken = code >= 400
+			if status.Broken {
+				status.ErrorKind = ErrorKindHTTP
+			}
+			continue
+		}
+		if errMsg, ok := failed[target]; ok {
+			status.Method = "crawl"
+			status.Broken = true
+			status.ErrorKind = ErrorKindRender
+			status.Error = errMsg
+			continue
+		}
+		jobs <- status
+	}
+	close(jobs)
+	wg.Wait()
+
+	results := make([]LinkStatus, 0, len(targets))
+	for _, target := range targets {
+		results = append(results, *statuses[target])
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Broken != results[j].Broken {
+			return results[i].Broken
+		}
+		return results[i].URL < results[j].URL
+	})
+
+	return results
+}
+
+// checkLink 通过 HTTP 请求验证链接
+func (c *Checker) checkLink(ctx context.Context, status *LinkStatus) {
+	var code int
+	var method string
+	var lastErr error
+
+	err := crawler.Retry(ctx, func() error {
+		code, method, lastErr = c.request(ctx, status.URL)
+		if lastErr != nil && crawler.IsRetryableError(lastErr) {
+			return lastErr
+		}
+		return nil
+	}, c.retryConfig)
+	if err == nil {
+		err = lastErr
+	}
+
+	status.Method = method
+	status.StatusCode = code
+	if err != nil {
+		status.Broken = true
+		status.ErrorKind = classifyError(err)
+		status.Error = err.Error()
+		return
+	}
+	if code >= 400 {
+		status.Broken = true
+		status.ErrorKind = ErrorKindHTTP
+		status.Error = http.StatusText(code)
+	}
+}
+
+// request 先发送 HEAD 请求，服务器不支持 HEAD 时改用只读取少量内容的 GET 请求
+func (c *Checker) request(ctx context.Context, url string) (int, string, error) {
+	code, err := c.do(ctx, http.MethodHead, url)
+	if err == nil && !headUnsupported(code) {
+		return code, http.MethodHead, nil
+	}
+
+	code, err = c.do(ctx, http.MethodGet, url)
+	return code, http.MethodGet, err
+}
+
+// do 发送单个请求并返回状态码
+func (c *Checker) do(ctx context.Context, method, url string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; FlareMind link checker)")
+	if method == http.MethodGet {
+		req.Header.Set("Range", "bytes=0-1023")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1024))
+
+	return resp.StatusCode, nil
+}
+
+// headUnsupported 判断状态码是否表示服务器不接受 HEAD 请求
+func headUnsupported(code int) bool {
+	return code == http.StatusMethodNotAllowed || code == http.StatusNotImplemented ||
+		code == http.StatusForbidden || code == http.StatusNotFound
+}
+
+// classifyError 将请求错误归类为 DNS、超时或其他网络错误
+func classifyError(err error) string {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return ErrorKindDNS
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return ErrorKindTimeout
+	}
+	return ErrorKindNetwork
+}
+
+// BrokenCount 统计失效链接数量
+func BrokenCount(links []LinkStatus) int {
+	count := 0
+	for _, link := range links {
+		if link.Broken {
+			count++
+		}
+	}
+	return count
+}
+
+// Summary 返回失效链接的简要描述
+func (s LinkStatus) Summary() string {
+	if s.StatusCode > 0 {
+		return fmt.Sprintf("%s: HTTP %d", s.URL, s.StatusCode)
+	}
+	return fmt.Sprintf("%s: %s (%s)", s.URL, s.ErrorKind, s.Error)
+}
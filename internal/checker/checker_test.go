@@ -0,0 +1,110 @@
+package checker
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"flaremind/internal/models"
+)
+
+func TestChecker_Check(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusOK)
+		case "/no-head":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	pages := []models.PageResult{
+		{URL: "https://example.com/", StatusCode: 200},
+		{URL: "https://example.com/gone", StatusCode: 410},
+	}
+	edges := []models.LinkEdge{
+		{Source: "https://example.com/", Target: "https://example.com/gone", Text: "Old page", Kind: models.LinkKindAnchor},
+		{Source: "https://example.com/", Target: "https://example.com/broken", Kind: models.LinkKindAnchor},
+		{Source: "https://example.com/", Target: server.URL + "/ok", Kind: models.LinkKindAnchor, External: true},
+		{Source: "https://example.com/", Target: server.URL + "/no-head", Kind: models.LinkKindAnchor, External: true},
+		{Source: "https://example.com/", Target: server.URL + "/missing", Kind: models.LinkKindAnchor, External: true},
+	}
+	failures := []models.FailedURL{{URL: "https://example.com/broken", Error: "net::ERR_NAME_NOT_RESOLVED"}}
+
+	links := NewChecker(5*time.Second, 2).Check(context.Background(), pages, edges, failures)
+	if len(links) != 5 {
+		t.Fatalf("Expected 5 checked links, got %d", len(links))
+	}
+
+	byURL := make(map[string]LinkStatus)
+	for _, link := range links {
+		byURL[link.URL] = link
+	}
+
+	if link := byURL["https://example.com/gone"]; !link.Broken || link.Method != "crawl" || link.StatusCode != 410 {
+		t.Errorf("Unexpected status for crawled 410 page: %+v", link)
+	}
+	if link := byURL["https://example.com/broken"]; !link.Broken || link.ErrorKind != ErrorKindRender {
+		t.Errorf("Unexpected status for failed page: %+v", link)
+	}
+	if link := byURL[server.URL+"/ok"]; link.Broken || link.Method != http.MethodHead {
+		t.Errorf("Unexpected status for ok link: %+v", link)
+	}
+	if link := byURL[server.URL+"/no-head"]; link.Broken || link.Method != http.MethodGet {
+		t.Errorf("Expected GET fallback for no-head link: %+v", link)
+	}
+	if link := byURL[server.URL+"/missing"]; !link.Broken || link.StatusCode != 404 {
+		t.Errorf("Unexpected status for missing link: %+v", link)
+	}
+	if BrokenCount(links) != 3 {
+		t.Errorf("Expected 3 broken links, got %d", BrokenCount(links))
+	}
+	if !links[0].Broken {
+		t.Error("Expected broken links to be listed first")
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	report := Report{
+		StartURL: "https://example.com/",
+		Checked:  2,
+		Broken:   1,
+		Links: []LinkStatus{
+			{URL: "https://example.com/gone", StatusCode: 404, Broken: true, ErrorKind: ErrorKindHTTP,
+				Sources: []LinkSource{{Page: "https://example.com/", Text: "Old page"}}},
+			{URL: "https://other.com/", StatusCode: 200, External: true},
+		},
+	}
+
+	var out bytes.Buffer
+	if err := WriteJUnit(&out, report); err != nil {
+		t.Fatalf("WriteJUnit() error = %v", err)
+	}
+
+	var document junitTestSuites
+	if err := xml.Unmarshal(out.Bytes(), &document); err != nil {
+		t.Fatalf("Failed to parse JUnit output: %v", err)
+	}
+	if document.Failures != 1 || len(document.Suites) != 2 {
+		t.Fatalf("Unexpected JUnit document: %+v", document)
+	}
+	failure := document.Suites[0].TestCases[0].Failure
+	if failure == nil || !strings.Contains(failure.Text, "Old page") {
+		t.Errorf("Expected failure to list the linking page, got %+v", failure)
+	}
+	if document.Suites[1].TestCases[0].Failure != nil {
+		t.Error("Expected external ok link to pass")
+	}
+}
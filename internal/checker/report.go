@@ -0,0 +1,96 @@
+package checker
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteJSON 以 JSON 格式写入检查报告
+func WriteJSON(w io.Writer, report Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// JUnit XML 文档结构
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit 以 JUnit XML 格式写入检查报告（每个链接一个测试用例）
+// 内链和外链分别作为两个测试套件，便于在 CI 中区分
+func WriteJUnit(w io.Writer, report Report) error {
+	suites := map[bool]*junitTestSuite{
+		false: {Name: "internal links"},
+		true:  {Name: "external links"},
+	}
+
+	for _, link := range report.Links {
+		suite := suites[link.External]
+		testCase := junitTestCase{
+			ClassName: report.StartURL,
+			Name:      link.URL,
+		}
+		if link.Broken {
+			var details strings.Builder
+			details.WriteString("Linked from:\n")
+			for _, source := range link.Sources {
+				if source.Text != "" {
+					fmt.Fprintf(&details, "  %s (%q)\n", source.Page, source.Text)
+				} else {
+					fmt.Fprintf(&details, "  %s\n", source.Page)
+				}
+			}
+			testCase.Failure = &junitFailure{
+				Message: link.Summary(),
+				Type:    link.ErrorKind,
+				Text:    details.String(),
+			}
+			suite.Failures++
+		}
+		suite.Tests++
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	document := junitTestSuites{
+		Tests:    report.Checked,
+		Failures: report.Broken,
+		Suites:   []junitTestSuite{*suites[false], *suites[true]},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,161 @@
+package crawler
+
+import (
+	"hash/fnv"
+	"math"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// DefaultBoilerplateMinPages 学习站点模板内容前，同一主机至少需要的页面数
+const DefaultBoilerplateMinPages = 5
+
+// boilerplateMinChars 块参与统计所需的最少字符数（中日韩文本按比例缩小）；
+// 更短的块（如文档中反复出现的“Parameters”“Example:”标题）是正文结构而非模板内容
+const boilerplateMinChars = 25
+
+// boilerplateBlockTags 参与统计的块级元素
+var boilerplateBlockTags = map[string]bool{
+	"p": true, "li": true, "blockquote": true, "pre": true, "table": true, "dl": true, "figure": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"div": true, "section": true, "aside": true, "form": true, "ul": true, "ol": true,
+	"nav": true, "header": true, "footer": true,
+}
+
+// BoilerplateDetector 统计同一主机下各页面正文中重复出现的 DOM 块，
+// 识别 cookie 提示、相关文章栏、订阅框等不在 nav/header/footer 中的全站模板内容
+type BoilerplateDetector struct {
+	threshold float64
+	minPages  int
+	hosts     map[string]*hostBlocks
+}
+
+// hostBlocks 单个主机的统计：页面数及每个块出现在多少个页面中
+type hostBlocks struct {
+	pages  int
+	counts map[uint64]int
+}
+
+// NewBoilerplateDetector 创建模板内容检测器
+// threshold 为块被视为模板内容所需出现的页面比例（0-1），minPages 为主机参与学习所需的最少页面数
+func NewBoilerplateDetector(threshold float64, minPages int) *BoilerplateDetector {
+	if minPages < 2 {
+		minPages = 2
+	}
+	return &BoilerplateDetector{
+		threshold: threshold,
+		minPages:  minPages,
+		hosts:     make(map[string]*hostBlocks),
+	}
+}
+
+// Add 记录一个页面的正文 HTML，同一页面中重复的块只计一次
+func (d *BoilerplateDetector) Add(pageURL string, content string) {
+	root, err := parseFragment(content)
+	if err != nil {
+		return
+	}
+
+	host := pageHost(pageURL)
+	stats := d.hosts[host]
+	if stats == nil {
+		stats = &hostBlocks{counts: make(map[uint64]int)}
+		d.hosts[host] = stats
+	}
+	stats.pages++
+
+	seen := make(map[uint64]bool)
+	eachBlock(root, func(node *html.Node, key uint64) bool {
+		if !seen[key] {
+			seen[key] = true
+			stats.counts[key]++
+		}
+		return true
+	})
+}
+
+// Clean 从页面正文中移除该主机的模板内容块，返回处理后的 HTML 和移除的块数
+// 主机页面数不足 minPages 时原样返回
+func (d *BoilerplateDetector) Clean(pageURL string, content string) (string, int, error) {
+	stats := d.hosts[pageHost(pageURL)]
+	if d.threshold <= 0 || stats == nil || stats.pages < d.minPages {
+		return content, 0, nil
+	}
+
+	// 至少出现在两个页面中，避免页面数较少时把单页内容当作模板
+	required := int(math.Ceil(d.threshold * float64(stats.pages)))
+	if required < 2 {
+		required = 2
+	}
+
+	root, err := parseFragment(content)
+	if err != nil {
+		return "", 0, err
+	}
+
+	var boilerplate []*html.Node
+	eachBlock(root, func(node *html.Node, key uint64) bool {
+		if stats.counts[key] >= required {
+			boilerplate = append(boilerplate, node)
+			return false
+		}
+		return true
+	})
+	if len(boilerplate) == 0 {
+		return content, 0, nil
+	}
+
+	for _, node := range boilerplate {
+		node.Parent.RemoveChild(node)
+	}
+	cleaned, err := goquery.NewDocumentFromNode(root).Find("body").Html()
+	if err != nil {
+		return "", 0, err
+	}
+	return cleaned, len(boilerplate), nil
+}
+
+// parseFragment 解析正文 HTML 片段
+func parseFragment(content string) (*html.Node, error) {
+	return html.Parse(strings.NewReader(content))
+}
+
+// eachBlock 按文档顺序遍历包含文本的块级元素，visit 返回 false 时不再进入该元素的子元素
+func eachBlock(node *html.Node, visit func(node *html.Node, key uint64) bool) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode {
+			continue
+		}
+		if boilerplateBlockTags[child.Data] {
+			if key, ok := blockKey(child); ok && !visit(child, key) {
+				continue
+			}
+		}
+		eachBlock(child, visit)
+	}
+}
+
+// blockKey 根据块的标签和规范化文本计算指纹，没有文本或文本过短的块返回 false
+func blockKey(node *html.Node) (uint64, bool) {
+	text := strings.ToLower(strings.Join(strings.Fields(goquery.NewDocumentFromNode(node).Text()), " "))
+	if text == "" || float64(textLength(text)) < scaledThreshold(boilerplateMinChars, text) {
+		return 0, false
+	}
+	h := fnv.New64a()
+	h.Write([]byte(node.Data))
+	h.Write([]byte{0})
+	h.Write([]byte(text))
+	return h.Sum64(), true
+}
+
+// pageHost 返回页面 URL 的主机名（小写）
+func pageHost(pageURL string) string {
+	parsed, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
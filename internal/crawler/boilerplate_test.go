@@ -0,0 +1,89 @@
+package crawler
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestBoilerplateDetector(t *testing.T) {
+	page := func(i int) string {
+		return fmt.Sprintf(`
+			<h1>Article %d</h1>
+			<p>Body of article %d with its own text.</p>
+			<div class="cookie"><p>We use cookies to improve your experience.</p><button>Accept</button></div>
+			<section><h2>Related articles from our newsroom</h2><ul><li><a href="/r/%d">Story %d</a></li></ul></section>
+		`, i, i, i, i+1)
+	}
+
+	detector := NewBoilerplateDetector(0.8, 3)
+	for i := 1; i <= 4; i++ {
+		detector.Add(fmt.Sprintf("https://news.example.com/a/%d", i), page(i))
+	}
+	detector.Add("https://other.example.com/a/1", page(1))
+
+	cleaned, removed, err := detector.Clean("https://news.example.com/a/1", page(1))
+	if err != nil {
+		t.Fatalf("Clean() error = %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("Clean() removed %d blocks, want 2 (cookie banner and related heading)", removed)
+	}
+	for _, want := range []string{"Article 1", "Body of article 1", "Story 2"} {
+		if !strings.Contains(cleaned, want) {
+			t.Errorf("expected %q to be kept, got %q", want, cleaned)
+		}
+	}
+	for _, unwanted := range []string{"We use cookies", "Related articles from our newsroom"} {
+		if strings.Contains(cleaned, unwanted) {
+			t.Errorf("expected boilerplate %q to be removed, got %q", unwanted, cleaned)
+		}
+	}
+
+	// 页面数不足的主机不学习
+	if _, removed, _ := detector.Clean("https://other.example.com/a/1", page(1)); removed != 0 {
+		t.Errorf("Clean() removed %d blocks from a host below the minimum pages", removed)
+	}
+}
+
+func TestBoilerplateDetector_Threshold(t *testing.T) {
+	detector := NewBoilerplateDetector(0.9, 2)
+	detector.Add("https://example.com/1", "<p>Shared note shown on some of the pages.</p><p>One.</p>")
+	detector.Add("https://example.com/2", "<p>Shared note shown on some of the pages.</p><p>Two.</p>")
+	detector.Add("https://example.com/3", "<p>Three.</p>")
+
+	// 出现在 2/3 的页面中，低于 0.9 的阈值
+	if cleaned, removed, _ := detector.Clean("https://example.com/1", "<p>Shared note shown on some of the pages.</p><p>One.</p>"); removed != 0 {
+		t.Errorf("Clean() = %q, %d, want nothing removed below the threshold", cleaned, removed)
+	}
+}
+
+func TestBoilerplateDetector_KeepsShortSharedBlocks(t *testing.T) {
+	page := func(i int) string {
+		return fmt.Sprintf(`
+			<h2>Parameters</h2><p>Function %d takes a context and returns an error.</p>
+			<p>Example:</p><pre>call(%d)</pre>
+			<h2>返回值</h2>
+			<ul><li>Next</li></ul>
+		`, i, i)
+	}
+
+	detector := NewBoilerplateDetector(0.5, 2)
+	for i := 1; i <= 4; i++ {
+		detector.Add(fmt.Sprintf("https://docs.example.com/api/%d", i), page(i))
+	}
+
+	// 每页都有但很短的标题和标签属于正文结构，不作为模板内容移除
+	cleaned, removed, err := detector.Clean("https://docs.example.com/api/1", page(1))
+	if err != nil {
+		t.Fatalf("Clean() error = %v", err)
+	}
+	if removed != 0 {
+		t.Errorf("Clean() removed %d blocks, want 0: %q", removed, cleaned)
+	}
+	for _, want := range []string{"Parameters", "Example:", "返回值", "Next"} {
+		if !strings.Contains(cleaned, want) {
+			t.Errorf("expected %q to be kept, got %q", want, cleaned)
+		}
+	}
+}
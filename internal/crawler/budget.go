@@ -0,0 +1,176 @@
+package crawler
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"flaremind/internal/models"
+)
+
+// BudgetTracker 页面预算跟踪器（在 URL 入队时检查并计数）
+type BudgetTracker struct {
+	mu        sync.Mutex
+	budgets   []models.PageBudget
+	counts    map[string]int             // 预算计数键 -> 已入队数量
+	exhausted map[string]map[string]bool // 预算计数键 -> 因预算耗尽被跳过的 URL
+}
+
+// NewBudgetTracker 创建新的预算跟踪器
+func NewBudgetTracker(budgets []models.PageBudget) *BudgetTracker {
+	return &BudgetTracker{
+		budgets:   budgets,
+		counts:    make(map[string]int),
+		exhausted: make(map[string]map[string]bool),
+	}
+}
+
+// frontier 预算跟踪器入队 URL 的队列（*queue.Queue）
+type frontier interface {
+	Contains(url string) bool
+	Add(url string) bool
+}
+
+// Reserve 检查 URL 是否在所有匹配的预算之内
+// 在预算内时加入队列，入队成功后计入各预算；任一预算耗尽时返回 false 并记录。
+// 已访问或已在队列中的 URL 直接返回 false，不计入耗尽数量
+func (b *BudgetTracker) Reserve(rawURL string, depth int, q frontier) bool {
+	if len(b.budgets) == 0 {
+		return q.Add(rawURL)
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if q.Contains(rawURL) {
+		return false
+	}
+
+	var keys []string
+	for _, budget := range b.budgets {
+		if !budgetMatches(budget, u.Path, depth) {
+			continue
+		}
+		key := budgetKey(budget, u.Host)
+		if b.counts[key] >= budget.MaxPages {
+			if b.exhausted[key] == nil {
+				b.exhausted[key] = make(map[string]bool)
+			}
+			b.exhausted[key][rawURL] = true
+			return false
+		}
+		keys = append(keys, key)
+	}
+
+	if !q.Add(rawURL) {
+		return false
+	}
+	for _, key := range keys {
+		b.counts[key]++
+	}
+
+	return true
+}
+
+// Exhausted 返回各预算因耗尽而跳过的 URL 数量
+func (b *BudgetTracker) Exhausted() map[string]int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.exhausted) == 0 {
+		return nil
+	}
+	result := make(map[string]int, len(b.exhausted))
+	for key, urls := range b.exhausted {
+		result[key] = len(urls)
+	}
+	return result
+}
+
+// budgetMatches 检查预算规则是否适用于指定路径和深度
+func budgetMatches(budget models.PageBudget, path string, depth int) bool {
+	if budget.MaxPages <= 0 {
+		return false
+	}
+	if budget.PathPrefix != "" && !strings.HasPrefix(path, budget.PathPrefix) {
+		return false
+	}
+	return depth >= budget.MinDepth
+}
+
+// budgetKey 生成预算计数键（按主机计数的预算附加主机名）
+func budgetKey(budget models.PageBudget, host string) string {
+	key := FormatBudget(budget)
+	if budget.PerHost {
+		key += " @" + host
+	}
+	return key
+}
+
+// FormatBudget 将预算规则格式化为与 ParseBudget 相同的语法
+func FormatBudget(budget models.PageBudget) string {
+	var parts []string
+	if budget.PathPrefix != "" {
+		parts = append(parts, "prefix="+budget.PathPrefix)
+	}
+	if budget.MinDepth > 0 {
+		parts = append(parts, "depth="+strconv.Itoa(budget.MinDepth))
+	}
+	if budget.PerHost {
+		parts = append(parts, "per-host")
+	}
+	parts = append(parts, "max="+strconv.Itoa(budget.MaxPages))
+	return strings.Join(parts, ",")
+}
+
+// ParseBudget 解析预算规则
+// 语法：逗号分隔的选项，例如 "prefix=/blog/,max=200" 或 "depth=2,per-host,max=50"
+//   - prefix=<路径前缀>：只统计该路径前缀下的页面
+//   - depth=<N>：只统计深度 >= N 的页面
+//   - per-host：按主机分别计数
+//   - max=<N>：最大页面数（必需）
+func ParseBudget(spec string) (models.PageBudget, error) {
+	var budget models.PageBudget
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "prefix":
+			budget.PathPrefix = value
+		case "depth":
+			depth, err := strconv.Atoi(value)
+			if err != nil || depth < 0 {
+				return budget, fmt.Errorf("invalid budget depth %q", value)
+			}
+			budget.MinDepth = depth
+		case "per-host":
+			budget.PerHost = true
+		case "max":
+			maxPages, err := strconv.Atoi(value)
+			if err != nil || maxPages <= 0 {
+				return budget, fmt.Errorf("invalid budget max %q", value)
+			}
+			budget.MaxPages = maxPages
+		default:
+			return budget, fmt.Errorf("unknown budget option %q", key)
+		}
+	}
+
+	if budget.MaxPages == 0 {
+		return budget, fmt.Errorf("budget %q is missing max", spec)
+	}
+
+	return budget, nil
+}
@@ -0,0 +1,83 @@
+package crawler
+
+import (
+	"testing"
+
+	"flaremind/internal/models"
+	"flaremind/internal/queue"
+)
+
+func TestParseBudget(t *testing.T) {
+	budget, err := ParseBudget("depth=2,per-host,max=50")
+	if err != nil {
+		t.Fatalf("ParseBudget() error = %v", err)
+	}
+	expected := models.PageBudget{MinDepth: 2, PerHost: true, MaxPages: 50}
+	if budget != expected {
+		t.Errorf("ParseBudget() = %+v, want %+v", budget, expected)
+	}
+	if FormatBudget(budget) != "depth=2,per-host,max=50" {
+		t.Errorf("FormatBudget() = %s", FormatBudget(budget))
+	}
+
+	for _, spec := range []string{"prefix=/blog/", "max=abc", "color=red,max=1"} {
+		if _, err := ParseBudget(spec); err == nil {
+			t.Errorf("Expected error for budget %q", spec)
+		}
+	}
+}
+
+func TestBudgetTracker(t *testing.T) {
+	tracker := NewBudgetTracker([]models.PageBudget{
+		{PathPrefix: "/blog/", MaxPages: 2},
+		{MinDepth: 2, PerHost: true, MaxPages: 1},
+	})
+	q := queue.NewQueue()
+
+	tests := []struct {
+		url      string
+		depth    int
+		expected bool
+	}{
+		{"https://example.com/blog/1", 1, true},
+		{"https://example.com/blog/2", 1, true},
+		{"https://example.com/blog/3", 1, false},
+		{"https://example.com/docs/1", 2, true},
+		{"https://example.com/docs/2", 2, false},
+		{"https://docs.example.com/1", 2, true},
+		{"https://example.com/docs/3", 1, true},
+	}
+
+	for _, tt := range tests {
+		if result := tracker.Reserve(tt.url, tt.depth, q); result != tt.expected {
+			t.Errorf("Reserve(%s, %d) = %v, want %v", tt.url, tt.depth, result, tt.expected)
+		}
+	}
+
+	exhausted := tracker.Exhausted()
+	if exhausted["prefix=/blog/,max=2"] != 1 {
+		t.Errorf("Expected 1 URL skipped by blog budget, got %v", exhausted)
+	}
+	if exhausted["depth=2,per-host,max=1 @example.com"] != 1 {
+		t.Errorf("Expected 1 URL skipped by per-host depth budget, got %v", exhausted)
+	}
+
+	// 重复 URL 不计入预算，预算耗尽后发现的重复 URL 也不计入耗尽数量
+	tracker = NewBudgetTracker([]models.PageBudget{{MaxPages: 1}})
+	q = queue.NewQueue()
+	q.Add("https://example.com/a")
+	if tracker.Reserve("https://example.com/a", 0, q) {
+		t.Error("Expected queued URL to be rejected")
+	}
+	if !tracker.Reserve("https://example.com/b", 0, q) {
+		t.Error("Expected rejected enqueue not to consume budget")
+	}
+	q.Pop()
+	q.MarkVisited("https://example.com/a")
+	for _, rawURL := range []string{"https://example.com/a", "https://example.com/b", "https://example.com/c"} {
+		tracker.Reserve(rawURL, 0, q)
+	}
+	if exhausted := tracker.Exhausted(); exhausted["max=1"] != 1 {
+		t.Errorf("Expected only the new URL to be counted as skipped, got %v", exhausted)
+	}
+}
@@ -0,0 +1,130 @@
+package crawler
+
+import (
+	"strings"
+
+	"flaremind/pkg/utils"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ExtractCanonical 从 HTML 的 <link rel="canonical"> 中提取规范 URL
+// 返回规范化后的绝对 URL，未找到或无效时返回空字符串
+func ExtractCanonical(html string, pageURL string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return ""
+	}
+
+	canonical := ""
+	doc.Find("link[rel][href]").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		if !hasRelToken(s.AttrOr("rel", ""), "canonical") {
+			return true
+		}
+		canonical = resolveCanonical(pageURL, s.AttrOr("href", ""))
+		return canonical == ""
+	})
+
+	return canonical
+}
+
+// CanonicalFromLinkHeader 从 HTTP Link 响应头中提取规范 URL
+// 例如：Link: <https://example.com/page>; rel="canonical"
+func CanonicalFromLinkHeader(values []string, pageURL string) string {
+	for _, value := range values {
+		for _, entry := range splitLinkHeader(value) {
+			target, params, ok := parseLinkHeaderEntry(entry)
+			if !ok {
+				continue
+			}
+			if hasRelToken(params["rel"], "canonical") {
+				if canonical := resolveCanonical(pageURL, target); canonical != "" {
+					return canonical
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// splitLinkHeader 按逗号拆分 Link 头（忽略 <> 和引号内的逗号）
+func splitLinkHeader(value string) []string {
+	var entries []string
+	var current strings.Builder
+	inAngle, inQuote := false, false
+
+	for _, r := range value {
+		switch {
+		case r == '<' && !inQuote:
+			inAngle = true
+		case r == '>' && !inQuote:
+			inAngle = false
+		case r == '"' && !inAngle:
+			inQuote = !inQuote
+		case r == ',' && !inAngle && !inQuote:
+			entries = append(entries, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	if strings.TrimSpace(current.String()) != "" {
+		entries = append(entries, current.String())
+	}
+
+	return entries
+}
+
+// parseLinkHeaderEntry 解析单个 Link 头条目，返回目标 URL 和参数
+func parseLinkHeaderEntry(entry string) (string, map[string]string, bool) {
+	entry = strings.TrimSpace(entry)
+	if !strings.HasPrefix(entry, "<") {
+		return "", nil, false
+	}
+	end := strings.Index(entry, ">")
+	if end < 0 {
+		return "", nil, false
+	}
+
+	target := strings.TrimSpace(entry[1:end])
+	params := make(map[string]string)
+	for _, part := range strings.Split(entry[end+1:], ";") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		params[strings.ToLower(strings.TrimSpace(key))] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+
+	return target, params, true
+}
+
+// hasRelToken 检查 rel 属性（空格分隔的多个值）是否包含指定值
+func hasRelToken(rel, token string) bool {
+	for _, field := range strings.Fields(strings.ToLower(rel)) {
+		if field == token {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveCanonical 将 canonical href 解析为规范化的绝对 URL
+func resolveCanonical(pageURL, href string) string {
+	href = strings.TrimSpace(href)
+	if href == "" {
+		return ""
+	}
+
+	absoluteURL, err := utils.ResolveURL(pageURL, href)
+	if err != nil {
+		return ""
+	}
+
+	normalized, err := utils.NormalizeURL(absoluteURL)
+	if err != nil || !utils.IsValidURL(normalized) {
+		return ""
+	}
+
+	return normalized
+}
@@ -0,0 +1,49 @@
+package crawler
+
+import "testing"
+
+func TestExtractCanonical(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{
+			name:     "Absolute",
+			html:     `<html><head><link rel="canonical" href="https://example.com/article"></head></html>`,
+			expected: "https://example.com/article",
+		},
+		{
+			name:     "Relative",
+			html:     `<html><head><link rel="Canonical" href="/article/"></head></html>`,
+			expected: "https://example.com/article",
+		},
+		{
+			name:     "Missing",
+			html:     `<html><head><link rel="stylesheet" href="/style.css"></head></html>`,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ExtractCanonical(tt.html, "https://example.com/article?utm_source=feed")
+			if result != tt.expected {
+				t.Errorf("ExtractCanonical() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCanonicalFromLinkHeader(t *testing.T) {
+	header := []string{`<https://example.com/style.css>; rel="preload", </docs/page>; rel="canonical"`}
+
+	result := CanonicalFromLinkHeader(header, "https://example.com/docs/page?v=2")
+	if result != "https://example.com/docs/page" {
+		t.Errorf("CanonicalFromLinkHeader() = %q, want %q", result, "https://example.com/docs/page")
+	}
+
+	if result := CanonicalFromLinkHeader(nil, "https://example.com/"); result != "" {
+		t.Errorf("Expected empty canonical for missing header, got %q", result)
+	}
+}
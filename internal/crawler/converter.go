@@ -1,206 +1,421 @@
 package crawler
 
 import (
-	"regexp"
+	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
 )
 
 // Converter Markdown 转换器
 type Converter struct {
+	tableFallback string   // 表格无法用 GFM 表示时的回退方式
+	base          *url.URL // 解析链接和图片地址的页面地址（PageToMarkdown 设置）
 }
 
 // NewConverter 创建新的转换器
 func NewConverter() *Converter {
-	return &Converter{}
+	return &Converter{
+		tableFallback: TableFallbackHTML,
+	}
+}
+
+// blockTags 块级元素，出现在内联内容中时会结束当前段落
+var blockTags = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "center": true, "dd": true,
+	"details": true, "dialog": true, "dir": true, "div": true, "dl": true, "dt": true, "fieldset": true,
+	"figcaption": true, "figure": true, "footer": true, "form": true, "h1": true, "h2": true, "h3": true,
+	"h4": true, "h5": true, "h6": true, "header": true, "hgroup": true, "hr": true, "li": true, "main": true,
+	"menu": true, "nav": true, "ol": true, "p": true, "pre": true, "section": true, "summary": true,
+	"table": true, "ul": true,
+}
+
+// skippedTags 不输出任何内容的元素
+var skippedTags = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true, "head": true,
 }
 
 // HTMLToMarkdown 将 HTML 转换为 Markdown
-func (c *Converter) HTMLToMarkdown(html string) (string, error) {
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+func (c *Converter) HTMLToMarkdown(rawHTML string) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(rawHTML))
 	if err != nil {
 		return "", err
 	}
 
-	var markdown strings.Builder
-	var lastWasTable bool
-
-	// 遍历所有元素
-	doc.Find("body").Children().Each(func(i int, s *goquery.Selection) {
-		isTable := goquery.NodeName(s) == "table"
-		elementContent := c.convertElement(s)
-		
-		// 如果是表格且前面不是表格，添加空行
-		if isTable && !lastWasTable {
-			markdown.WriteString("\n")
-		}
-		
-		markdown.WriteString(elementContent)
-		
-		// 表格后不需要立即添加空行，会在下一个非表格元素时添加
-		if !isTable {
-			markdown.WriteString("\n\n")
-		} else {
-			markdown.WriteString("\n")
-		}
-		
-		lastWasTable = isTable
-	})
-
-	result := markdown.String()
-	
-	// 确保表格前有空行（表格以 | 开头）
-	// 匹配模式：非空行后紧跟表格行（以 | 开头），但表格行之间不应该有空行
-	result = regexp.MustCompile(`([^\n\s])\n\n(\|)`).ReplaceAllString(result, "$1\n\n$2")
-	// 移除表格行之间的空行
-	result = regexp.MustCompile(`(\|.*\|)\n\n(\|)`).ReplaceAllString(result, "$1\n$2")
-	
-	// 清理多余的空行
-	result = regexp.MustCompile(`\n{4,}`).ReplaceAllString(result, "\n\n\n")
-	result = strings.TrimSpace(result)
-
-	return result, nil
-}
-
-// convertElement 转换单个元素
-func (c *Converter) convertElement(s *goquery.Selection) string {
-	tagName := goquery.NodeName(s)
-	var result strings.Builder
-
-	switch tagName {
-	case "h1":
-		result.WriteString("# " + strings.TrimSpace(s.Text()) + "\n")
-	case "h2":
-		result.WriteString("## " + strings.TrimSpace(s.Text()) + "\n")
-	case "h3":
-		result.WriteString("### " + strings.TrimSpace(s.Text()) + "\n")
-	case "h4":
-		result.WriteString("#### " + strings.TrimSpace(s.Text()) + "\n")
-	case "h5":
-		result.WriteString("##### " + strings.TrimSpace(s.Text()) + "\n")
-	case "h6":
-		result.WriteString("###### " + strings.TrimSpace(s.Text()) + "\n")
-	case "p":
-		result.WriteString(c.convertInline(s) + "\n")
-	case "ul", "ol":
-		s.Find("li").Each(func(i int, li *goquery.Selection) {
-			prefix := "- "
-			if tagName == "ol" {
-				prefix = strings.Repeat(" ", len(string(rune(i+1)))+1) + "1. "
+	body := doc.Find("body")
+	if body.Length() == 0 {
+		return "", nil
+	}
+	return strings.Join(c.renderBlocks(body.Get(0)), "\n\n"), nil
+}
+
+// renderBlocks 将节点的子节点渲染为 Markdown 块
+func (c *Converter) renderBlocks(node *html.Node) []string {
+	var children []*html.Node
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		children = append(children, child)
+	}
+	return c.renderBlockNodes(children)
+}
+
+// renderBlockNodes 将节点渲染为 Markdown 块：相邻的文本和内联元素组成段落，块级元素各自渲染
+func (c *Converter) renderBlockNodes(nodes []*html.Node) []string {
+	var blocks []string
+	var inline []*html.Node
+	// 相邻的同类列表需要换用另一种标记，否则会被解析为同一个列表
+	lastList, alternate := "", false
+
+	flush := func() {
+		if paragraph := c.renderInlineNodes(inline); paragraph != "" {
+			blocks = append(blocks, displayMathBlock(paragraph))
+			lastList = ""
+		}
+		inline = nil
+	}
+
+	for _, child := range nodes {
+		// 公式：独立公式单独成块，行内公式归入段落；MathJax 2 的渲染结果由随后的 script 代替
+		if tex, display, ok := mathSource(child); ok {
+			if display {
+				flush()
+				blocks = append(blocks, displayMathBlock(mathMarkdown(tex, true)))
+				lastList = ""
+			} else {
+				inline = append(inline, child)
 			}
-			result.WriteString(prefix + strings.TrimSpace(c.convertInline(li)) + "\n")
-		})
-	case "blockquote":
-		lines := strings.Split(strings.TrimSpace(s.Text()), "\n")
-		for _, line := range lines {
-			if strings.TrimSpace(line) != "" {
-				result.WriteString("> " + strings.TrimSpace(line) + "\n")
+			continue
+		}
+		if isMathJaxOutput(child) {
+			continue
+		}
+		if child.Type == html.ElementNode && skippedTags[child.Data] {
+			continue
+		}
+		if !isBlockNode(child) {
+			inline = append(inline, child)
+			continue
+		}
+		flush()
+		var block, kind string
+		switch child.Data {
+		case "ul", "ol":
+			kind = child.Data
+			block, alternate = c.renderList(child, kind == lastList && !alternate)
+		case "table":
+			// 以列表形式回退的表格同样不能与相邻的无序列表合并
+			next := lastList == "ul" && !alternate
+			var listed bool
+			if block, listed = c.renderTable(child, next); listed {
+				kind, alternate = "ul", next
 			}
+		default:
+			block = c.renderBlock(child)
 		}
+		if block != "" {
+			blocks = append(blocks, block)
+			lastList = kind
+		}
+	}
+	flush()
+
+	return blocks
+}
+
+// renderBlock 渲染单个块级元素（列表和表格由 renderBlockNodes 渲染）
+func (c *Converter) renderBlock(node *html.Node) string {
+	switch node.Data {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		text := strings.ReplaceAll(c.renderInline(node), hardBreak, " ")
+		if text == "" {
+			return ""
+		}
+		level := int(node.Data[1] - '0')
+		return strings.Repeat("#", level) + " " + escapeHeading(text)
+	case "p":
+		return displayMathBlock(c.renderInline(node))
+	case "blockquote":
+		return quoteLines(strings.Join(c.renderBlocks(node), "\n\n"))
 	case "pre":
-		code := s.Text()
-		// 检查是否包含 code 标签
-		if s.Find("code").Length() > 0 {
-			lang := s.Find("code").First().AttrOr("class", "")
-			if strings.HasPrefix(lang, "language-") {
-				lang = strings.TrimPrefix(lang, "language-")
-			}
-			result.WriteString("```" + lang + "\n")
-			result.WriteString(strings.TrimSpace(code) + "\n")
-			result.WriteString("```\n")
-		} else {
-			result.WriteString("```\n")
-			result.WriteString(strings.TrimSpace(code) + "\n")
-			result.WriteString("```\n")
-		}
-	case "code":
-		result.WriteString("`" + strings.TrimSpace(s.Text()) + "`")
-	case "img":
-		alt := s.AttrOr("alt", "")
-		src := s.AttrOr("src", "")
-		result.WriteString("![" + alt + "](" + src + ")\n")
-	case "a":
-		text := strings.TrimSpace(s.Text())
-		href := s.AttrOr("href", "")
-		result.WriteString("[" + text + "](" + href + ")")
-	case "strong", "b":
-		result.WriteString("**" + strings.TrimSpace(s.Text()) + "**")
-	case "em", "i":
-		result.WriteString("*" + strings.TrimSpace(s.Text()) + "*")
+		return renderCodeBlock(node)
+	case "figcaption":
+		// 图片说明以强调显示；本身以 * 开头或结尾时不再包裹
+		caption := strings.ReplaceAll(c.renderInline(node), hardBreak, " ")
+		if caption == "" || strings.HasPrefix(caption, "*") || strings.HasSuffix(caption, "*") {
+			return caption
+		}
+		return "*" + caption + "*"
+	case "details":
+		return c.renderDetails(node)
+	case "dl":
+		return c.renderDefinitionList(node)
 	case "hr":
-		result.WriteString("---\n")
-	case "table":
-		result.WriteString(c.convertTable(s))
-	default:
-		// 递归处理子元素
-		s.Contents().Each(func(i int, child *goquery.Selection) {
-			if goquery.NodeName(child) == "#text" {
-				text := strings.TrimSpace(child.Text())
-				if text != "" {
-					result.WriteString(text + " ")
+		return "---"
+	}
+
+	// 其他容器元素（div、section、li 等）：渲染子节点
+	return strings.Join(c.renderBlocks(node), "\n\n")
+}
+
+// renderDetails 渲染折叠块：Markdown 没有对应语法，<details> 和 <summary> 以 HTML 输出，内容仍为 Markdown
+func (c *Converter) renderDetails(node *html.Node) string {
+	var summary *html.Node
+	var content []*html.Node
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if summary == nil && child.Type == html.ElementNode && child.Data == "summary" {
+			summary = child
+			continue
+		}
+		content = append(content, child)
+	}
+
+	open := "<details>"
+	if hasAttr(node, "open") {
+		open = "<details open>"
+	}
+	parts := []string{open}
+	if summary != nil {
+		if text := strings.TrimSpace(whitespacePattern.ReplaceAllString(textContent(summary), " ")); text != "" {
+			parts[0] += "\n<summary>" + html.EscapeString(text) + "</summary>"
+		}
+	}
+	parts = append(parts, c.renderBlockNodes(content)...)
+	parts = append(parts, "</details>")
+	return strings.Join(parts, "\n\n")
+}
+
+// renderDefinitionList 渲染定义列表，使用 PHP Markdown Extra、Pandoc 等支持的语法：
+// 术语各占一行，其后每个定义以 ": " 开头，定义中的后续内容缩进两格
+func (c *Converter) renderDefinitionList(list *html.Node) string {
+	var groups []string
+	var group []string
+	defined := false // 当前组是否已有定义（之后的术语开始新的一组）
+
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+			switch child.Data {
+			case "dt":
+				if defined {
+					groups = append(groups, strings.Join(group, "\n"))
+					group, defined = nil, false
 				}
-			} else {
-				result.WriteString(c.convertElement(child))
+				if term := strings.ReplaceAll(c.renderInline(child), hardBreak, " "); term != "" {
+					group = append(group, term)
+				}
+			case "dd":
+				if blocks := c.renderBlocks(child); len(blocks) > 0 {
+					group = append(group, ": "+indentLines(strings.Join(blocks, "\n\n"), 2))
+					defined = true
+				}
+			case "div":
+				// HTML 允许用 div 包裹每组术语和定义
+				walk(child)
 			}
-		})
+		}
+	}
+	walk(list)
+	if len(group) > 0 {
+		groups = append(groups, strings.Join(group, "\n"))
 	}
+	return strings.Join(groups, "\n\n")
+}
 
-	return result.String()
+// listItem 列表项及其标记
+type listItem struct {
+	node   *html.Node
+	extra  []*html.Node // 直接出现在列表中、归属于该项的其他节点（如不规范嵌套的子列表）
+	number int
 }
 
-// convertInline 转换内联元素
-func (c *Converter) convertInline(s *goquery.Selection) string {
-	var result strings.Builder
+// renderList 渲染有序或无序列表，嵌套内容按标记宽度缩进
+//
+// alternate 为 true 时使用另一种标记（* 或 N)）。Markdown 只能指定有序列表的起始编号，
+// 编号不连续时从该项起拆分为新列表并切换标记；返回最后一段是否使用了另一种标记。
+func (c *Converter) renderList(list *html.Node, alternate bool) (string, bool) {
+	ordered := list.Data == "ol"
+	number := 1
+	if start, err := strconv.Atoi(strings.TrimSpace(attrValue(list, "start"))); ordered && err == nil {
+		number = start
+	}
 
-	s.Contents().Each(func(i int, child *goquery.Selection) {
-		nodeName := goquery.NodeName(child)
-		if nodeName == "#text" {
-			result.WriteString(strings.TrimSpace(child.Text()) + " ")
-		} else {
-			result.WriteString(c.convertElement(child))
+	var items []*listItem
+	for child := list.FirstChild; child != nil; child = child.NextSibling {
+		switch {
+		case child.Type == html.ElementNode && child.Data == "li":
+			if value, err := strconv.Atoi(strings.TrimSpace(attrValue(child, "value"))); ordered && err == nil {
+				number = value
+			}
+			items = append(items, &listItem{node: child, number: number})
+			number++
+		case child.Type == html.TextNode && strings.TrimSpace(child.Data) == "":
+		case child.Type == html.CommentNode:
+		case len(items) > 0:
+			items[len(items)-1].extra = append(items[len(items)-1].extra, child)
+		default:
+			items = append(items, &listItem{extra: []*html.Node{child}, number: number})
+			number++
+		}
+	}
+
+	// 任一项包含多个段落时使用松散列表（项之间空一行）
+	loose := false
+	contents := make([][]string, len(items))
+	for i, item := range items {
+		if item.node != nil {
+			contents[i] = c.renderBlocks(item.node)
+			loose = loose || hasChildElement(item.node, "p") && len(contents[i]) > 1
+		}
+		contents[i] = append(contents[i], c.renderBlockNodes(item.extra)...)
+	}
+	separator := "\n"
+	if loose {
+		separator = "\n\n"
+	}
+
+	var rendered strings.Builder
+	for i, item := range items {
+		if i > 0 {
+			if ordered && item.number != items[i-1].number+1 {
+				rendered.WriteString("\n\n")
+				alternate = !alternate
+			} else {
+				rendered.WriteString(separator)
+			}
 		}
-	})
 
-	return strings.TrimSpace(result.String())
+		marker := "- "
+		switch {
+		case ordered && alternate:
+			marker = strconv.Itoa(item.number) + ") "
+		case ordered:
+			marker = strconv.Itoa(item.number) + ". "
+		case alternate:
+			marker = "* "
+		}
+		rendered.WriteString(marker + indentLines(strings.Join(contents[i], separator), len(marker)))
+	}
+
+	return rendered.String(), alternate
 }
 
-// convertTable 转换表格
-func (c *Converter) convertTable(s *goquery.Selection) string {
-	var result strings.Builder
+// renderCodeBlock 渲染代码块，围栏长度大于代码中最长的连续反引号
+func renderCodeBlock(node *html.Node) string {
+	pre := goquery.NewDocumentFromNode(node).Selection
+	code := pre.Text()
+	code = strings.TrimPrefix(code, "\n")
+	code = strings.TrimRight(code, "\n ")
 
-	// 在表格前添加空行，确保表格能正常渲染
-	result.WriteString("\n")
+	lang := codeLanguage(pre)
+	if inner := pre.Find("code").First(); lang == "" && inner.Length() > 0 {
+		lang = codeLanguage(inner)
+	}
 
-	// 处理表头
-	headers := []string{}
-	s.Find("thead tr, tr").First().Find("th, td").Each(func(i int, th *goquery.Selection) {
-		headers = append(headers, strings.TrimSpace(th.Text()))
-	})
+	fence := strings.Repeat("`", max(3, longestRun(code, '`')+1))
+	return fence + lang + "\n" + code + "\n" + fence
+}
 
-	if len(headers) > 0 {
-		// 写入表头
-		result.WriteString("| " + strings.Join(headers, " | ") + " |\n")
-		// 写入分隔符
-		separator := make([]string, len(headers))
-		for i := range separator {
-			separator[i] = "---"
+// codeLanguage 从 class（language-xxx 或 lang-xxx）中读取代码语言
+func codeLanguage(s *goquery.Selection) string {
+	for _, class := range strings.Fields(s.AttrOr("class", "")) {
+		for _, prefix := range []string{"language-", "lang-"} {
+			if strings.HasPrefix(class, prefix) {
+				return strings.TrimPrefix(class, prefix)
+			}
 		}
-		result.WriteString("| " + strings.Join(separator, " | ") + " |\n")
+	}
+	return ""
+}
 
-		// 处理表格行
-		s.Find("tbody tr, tr").Slice(1, goquery.ToEnd).Each(func(i int, tr *goquery.Selection) {
-			cells := []string{}
-			tr.Find("td").Each(func(j int, td *goquery.Selection) {
-				cells = append(cells, strings.TrimSpace(td.Text()))
-			})
-			if len(cells) > 0 {
-				result.WriteString("| " + strings.Join(cells, " | ") + " |\n")
-			}
-		})
+// quoteLines 为每一行加上引用标记
+func quoteLines(text string) string {
+	if text == "" {
+		return ""
 	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = ">"
+		} else {
+			lines[i] = "> " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
 
-	return result.String()
+// indentLines 缩进除第一行以外的非空行
+func indentLines(text string, width int) string {
+	lines := strings.Split(text, "\n")
+	padding := strings.Repeat(" ", width)
+	for i := 1; i < len(lines); i++ {
+		if lines[i] != "" {
+			lines[i] = padding + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
 }
 
+// isBlockNode 判断节点是否应按块级元素渲染：块级标签，或包含块级元素的未知元素
+func isBlockNode(node *html.Node) bool {
+	if node.Type != html.ElementNode {
+		return false
+	}
+	if blockTags[node.Data] {
+		return true
+	}
+	if inlineTags[node.Data] {
+		return false
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if isBlockNode(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// displayMathBlock 只包含一个独立公式的段落改为多行的 $$ 公式块
+func displayMathBlock(paragraph string) string {
+	if strings.HasPrefix(paragraph, "$$") && strings.HasSuffix(paragraph, "$$") && strings.Count(paragraph, "$$") == 2 {
+		return "$$\n" + strings.TrimSuffix(strings.TrimPrefix(paragraph, "$$"), "$$") + "\n$$"
+	}
+	return paragraph
+}
 
+// hasAttr 判断元素是否有指定属性（不论取值）
+func hasAttr(node *html.Node, name string) bool {
+	for _, attr := range node.Attr {
+		if attr.Key == name {
+			return true
+		}
+	}
+	return false
+}
+
+// hasChildElement 判断节点是否有指定标签的子元素
+func hasChildElement(node *html.Node, tag string) bool {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.Data == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// longestRun 返回字符串中字符 r 的最长连续出现次数
+func longestRun(s string, r rune) int {
+	longest, current := 0, 0
+	for _, c := range s {
+		if c == r {
+			current++
+			longest = max(longest, current)
+		} else {
+			current = 0
+		}
+	}
+	return longest
+}
@@ -0,0 +1,95 @@
+package crawler
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	// entityPattern 会被解析为 HTML 字符引用的 & 序列
+	entityPattern = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[A-Za-z][A-Za-z0-9]*);`)
+	// listMarkerPattern 行首会被解析为有序列表的数字
+	listMarkerPattern = regexp.MustCompile(`^([0-9]{1,9})([.)])( |$)`)
+	// underlinePattern 会被解析为 Setext 标题下划线或分隔线的行
+	underlinePattern = regexp.MustCompile(`^(?:=+|-+) *$`)
+	// closingHashesPattern ATX 标题末尾会被当作结束标记去掉的 #
+	closingHashesPattern = regexp.MustCompile(` (#+)$`)
+)
+
+// escapeText 转义文本中会被解析为内联格式的字符
+//
+// 反斜杠、*、`、[、]、~、$（公式分隔符）始终转义；_ 仅在不处于两个字母或数字之间时转义（snake_case 保持原样）；
+// < 仅在可能构成 HTML 标签或自动链接时转义；& 仅在构成字符引用时转义。
+func escapeText(text string) string {
+	runes := []rune(text)
+	var b strings.Builder
+	for i, r := range runes {
+		switch r {
+		case emphasisOpen, emphasisClose:
+			// 与强调占位符相同的控制字符没有可见内容，直接去掉
+			continue
+		case '\\', '*', '`', '[', ']', '~', '$':
+			b.WriteByte('\\')
+		case '_':
+			if i == 0 || i == len(runes)-1 || !isWordRune(runes[i-1]) || !isWordRune(runes[i+1]) {
+				b.WriteByte('\\')
+			}
+		case '<':
+			if i == len(runes)-1 || unicode.IsLetter(runes[i+1]) || strings.ContainsRune("/!?", runes[i+1]) {
+				b.WriteByte('\\')
+			}
+		case '&':
+			if entityPattern.MatchString(string(runes[i:])) {
+				b.WriteByte('\\')
+			}
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// escapeLineStart 转义行首会被解析为块级结构（标题、列表、引用、分隔线）的字符
+func escapeLineStart(line string) string {
+	switch {
+	case line == "":
+		return line
+	case underlinePattern.MatchString(line):
+		return `\` + line
+	case line[0] == '>':
+		return `\` + line
+	case line[0] == '#':
+		rest := strings.TrimLeft(line, "#")
+		if len(line)-len(rest) <= 6 && (rest == "" || rest[0] == ' ') {
+			return `\` + line
+		}
+	case (line[0] == '-' || line[0] == '+') && (len(line) == 1 || line[1] == ' '):
+		return `\` + line
+	}
+	return listMarkerPattern.ReplaceAllString(line, `$1\$2$3`)
+}
+
+// escapeHeading 转义标题末尾会被当作结束标记的 #
+func escapeHeading(text string) string {
+	return closingHashesPattern.ReplaceAllString(text, ` \$1`)
+}
+
+// escapeTableCell 转义表格单元格中的竖线
+func escapeTableCell(text string) string {
+	return strings.ReplaceAll(text, "|", `\|`)
+}
+
+// linkDestination 返回可直接写入 (...) 的链接地址：空格和反斜杠百分号编码，包含括号或尖括号时使用 <...> 形式
+func linkDestination(url string) string {
+	url = strings.NewReplacer(" ", "%20", `\`, "%5C").Replace(url)
+	if !strings.ContainsAny(url, "()<>") {
+		return url
+	}
+	url = strings.NewReplacer("<", `\<`, ">", `\>`).Replace(url)
+	return "<" + url + ">"
+}
+
+// isWordRune 判断字符是否为字母或数字
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,286 @@
+package crawler
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// hardBreak Markdown 硬换行（行尾两个空格）
+const hardBreak = "  \n"
+
+// lineBreakMarker 渲染内联内容时 <br> 的占位符，整理空白后替换为 hardBreak
+const lineBreakMarker = "\x00"
+
+// 渲染内联内容时强调标记的占位符，后跟 emphasisStyles 中的样式；两侧的字符确定后再决定输出 Markdown 标记还是 HTML 标签
+const (
+	emphasisOpen  = '\x01'
+	emphasisClose = '\x02'
+)
+
+// emphasisStyle 强调元素的 Markdown 标记及标记无法生效时使用的 HTML 标签
+type emphasisStyle struct {
+	delimiter string
+	tag       string
+}
+
+// emphasisStyles 占位符中的样式 -> 强调元素的输出方式
+var emphasisStyles = map[byte]emphasisStyle{
+	's': {"**", "strong"},
+	'e': {"*", "em"},
+	'd': {"~~", "del"},
+}
+
+// inlineTags 内联元素，即使包含块级元素也按内联内容渲染
+var inlineTags = map[string]bool{
+	"a": true, "abbr": true, "b": true, "bdi": true, "bdo": true, "big": true, "br": true, "button": true,
+	"cite": true, "code": true, "data": true, "del": true, "dfn": true, "em": true, "font": true, "i": true,
+	"img": true, "input": true, "ins": true, "kbd": true, "label": true, "mark": true, "picture": true,
+	"q": true, "s": true, "samp": true, "small": true, "span": true, "strike": true, "strong": true,
+	"sub": true, "sup": true, "time": true, "tt": true, "u": true, "var": true, "wbr": true,
+}
+
+var (
+	whitespacePattern = regexp.MustCompile(`[ \t\n\r\f]+`)
+	multiSpacePattern = regexp.MustCompile(` {2,}`)
+	lineBreakPattern  = regexp.MustCompile(` *` + lineBreakMarker + ` *`)
+)
+
+// renderInline 将节点的子节点渲染为一行内联 Markdown
+func (c *Converter) renderInline(node *html.Node) string {
+	var b strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		c.writeInline(&b, child)
+	}
+	return finishInline(b.String())
+}
+
+// renderInlineNodes 将一组节点渲染为一行内联 Markdown
+func (c *Converter) renderInlineNodes(nodes []*html.Node) string {
+	var b strings.Builder
+	for _, node := range nodes {
+		c.writeInline(&b, node)
+	}
+	return finishInline(b.String())
+}
+
+// finishInline 合并连续空白，去除换行占位符两侧及首尾的空白，输出强调标记，转义每行行首后替换为硬换行
+func finishInline(raw string) string {
+	raw = resolveEmphasis(raw)
+	raw = multiSpacePattern.ReplaceAllString(raw, " ")
+	raw = lineBreakPattern.ReplaceAllString(raw, lineBreakMarker)
+	raw = strings.Trim(raw, " "+lineBreakMarker)
+	lines := strings.Split(raw, lineBreakMarker)
+	for i, line := range lines {
+		lines[i] = escapeLineStart(line)
+	}
+	return strings.Join(lines, hardBreak)
+}
+
+// writeInline 写入单个节点的内联 Markdown（空白尚未整理）
+func (c *Converter) writeInline(b *strings.Builder, node *html.Node) {
+	switch node.Type {
+	case html.TextNode:
+		b.WriteString(escapeText(whitespacePattern.ReplaceAllString(node.Data, " ")))
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	if tex, display, ok := mathSource(node); ok {
+		b.WriteString(mathMarkdown(tex, display))
+		return
+	}
+	if isMathJaxOutput(node) {
+		return
+	}
+
+	switch node.Data {
+	case "script", "style", "noscript", "template":
+	case "br":
+		b.WriteString(lineBreakMarker)
+	case "strong", "b":
+		c.writeEmphasis(b, node, 's')
+	case "em", "i":
+		c.writeEmphasis(b, node, 'e')
+	case "del", "s", "strike":
+		c.writeEmphasis(b, node, 'd')
+	case "sup", "sub", "kbd", "mark":
+		// 没有对应的 Markdown 语法，以内联 HTML 输出，标签内仍为 Markdown
+		c.writeDelimited(b, node, "<"+node.Data+">", "</"+node.Data+">")
+	case "code", "tt", "samp":
+		b.WriteString(codeSpan(textContent(node)))
+	case "a":
+		href := c.linkHref(node)
+		if href == "" {
+			c.writeChildren(b, node)
+			return
+		}
+		// 紧挨着的 ! 会让链接变成图片
+		if written := b.String(); strings.HasSuffix(written, "!") {
+			b.Reset()
+			b.WriteString(strings.TrimSuffix(written, "!") + `\!`)
+		}
+		c.writeDelimited(b, node, "[", "]("+linkDestination(href)+")")
+	case "img":
+		if src := c.imageSource(node); src != "" {
+			alt := whitespacePattern.ReplaceAllString(strings.TrimSpace(attrValue(node, "alt")), " ")
+			b.WriteString("![" + escapeText(alt) + "](" + linkDestination(src) + ")")
+		}
+	default:
+		// 出现在内联内容中的块级元素与两侧内容以空格分隔
+		if blockTags[node.Data] {
+			b.WriteString(" ")
+			c.writeChildren(b, node)
+			b.WriteString(" ")
+			return
+		}
+		c.writeChildren(b, node)
+	}
+}
+
+// writeChildren 写入子节点的内联 Markdown
+func (c *Converter) writeChildren(b *strings.Builder, node *html.Node) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		c.writeInline(b, child)
+	}
+}
+
+// writeDelimited 用 open 和 close 包裹子节点的内联 Markdown，首尾空白移到标记外侧；内容为空时不输出标记
+func (c *Converter) writeDelimited(b *strings.Builder, node *html.Node, open string, close string) {
+	var inner strings.Builder
+	c.writeChildren(&inner, node)
+	raw := inner.String()
+
+	trimmed := strings.Trim(raw, " "+lineBreakMarker)
+	if trimmed == "" {
+		b.WriteString(raw)
+		return
+	}
+	if strings.HasPrefix(raw, " ") {
+		b.WriteString(" ")
+	}
+	b.WriteString(open + trimmed + close)
+	if strings.HasSuffix(raw, " ") {
+		b.WriteString(" ")
+	}
+}
+
+// writeEmphasis 写入强调元素，标记先以占位符输出，由 resolveEmphasis 替换
+func (c *Converter) writeEmphasis(b *strings.Builder, node *html.Node, style byte) {
+	c.writeDelimited(b, node, string([]byte{emphasisOpen, style}), string([]byte{emphasisClose, style}))
+}
+
+// resolveEmphasis 将强调占位符替换为 Markdown 标记
+//
+// CommonMark 要求开始标记为左侧邻接（left-flanking）、结束标记为右侧邻接（right-flanking）：
+// 内容以标点开头或结尾且标记外侧紧挨着字母或数字时（如 a*(x)*b），标记会被当作普通字符，此时改用 HTML 标签。
+func resolveEmphasis(raw string) string {
+	if !strings.ContainsAny(raw, string([]rune{emphasisOpen, emphasisClose})) {
+		return raw
+	}
+
+	// 先把所有占位符替换为 Markdown 标记，记录每个强调元素的标记位置
+	type span struct {
+		style                                    byte
+		openStart, openEnd, closeStart, closeEnd int
+	}
+	var text strings.Builder
+	var open, spans []span
+	for i := 0; i < len(raw); i++ {
+		switch raw[i] {
+		case emphasisOpen:
+			i++
+			s := span{style: raw[i], openStart: text.Len()}
+			text.WriteString(emphasisStyles[s.style].delimiter)
+			s.openEnd = text.Len()
+			open = append(open, s)
+		case emphasisClose:
+			i++
+			s := open[len(open)-1]
+			open = open[:len(open)-1]
+			s.closeStart = text.Len()
+			text.WriteString(emphasisStyles[s.style].delimiter)
+			s.closeEnd = text.Len()
+			spans = append(spans, s)
+		default:
+			text.WriteByte(raw[i])
+		}
+	}
+	result := text.String()
+
+	// 标记之间的相对位置不变，且 HTML 标签的 < > 与标记一样属于标点，因此每个强调元素可以单独判断
+	type replacement struct {
+		start, end int
+		value      string
+	}
+	var replacements []replacement
+	for _, s := range spans {
+		before, _ := utf8.DecodeLastRuneInString(result[:s.openStart])
+		first, _ := utf8.DecodeRuneInString(result[s.openEnd:])
+		last, _ := utf8.DecodeLastRuneInString(result[:s.closeStart])
+		after, _ := utf8.DecodeRuneInString(result[s.closeEnd:])
+		if leftFlanking(before, first) && rightFlanking(last, after) {
+			continue
+		}
+		tag := emphasisStyles[s.style].tag
+		replacements = append(replacements,
+			replacement{s.openStart, s.openEnd, "<" + tag + ">"},
+			replacement{s.closeStart, s.closeEnd, "</" + tag + ">"})
+	}
+	sort.Slice(replacements, func(i, j int) bool { return replacements[i].start > replacements[j].start })
+	for _, r := range replacements {
+		result = result[:r.start] + r.value + result[r.end:]
+	}
+	return result
+}
+
+// leftFlanking 判断前一个字符为 before、后一个字符为 next 的标记能否开始强调
+func leftFlanking(before rune, next rune) bool {
+	return !isFlankingSpace(next) && (!isFlankingPunct(next) || isFlankingSpace(before) || isFlankingPunct(before))
+}
+
+// rightFlanking 判断前一个字符为 prev、后一个字符为 after 的标记能否结束强调
+func rightFlanking(prev rune, after rune) bool {
+	return !isFlankingSpace(prev) && (!isFlankingPunct(prev) || isFlankingSpace(after) || isFlankingPunct(after))
+}
+
+// isFlankingSpace 判断字符是否按空白处理（行首行尾、换行占位符视为空白）
+func isFlankingSpace(r rune) bool {
+	return r == utf8.RuneError || r == 0 || unicode.IsSpace(r)
+}
+
+// isFlankingPunct 判断字符是否为 CommonMark 意义上的标点（包括符号）
+func isFlankingPunct(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+// codeSpan 渲染行内代码，反引号数量多于代码中最长的连续反引号
+func codeSpan(code string) string {
+	code = whitespacePattern.ReplaceAllString(code, " ")
+	if strings.TrimSpace(code) == "" {
+		return code
+	}
+	fence := strings.Repeat("`", longestRun(code, '`')+1)
+	if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") || (strings.HasPrefix(code, " ") && strings.HasSuffix(code, " ")) {
+		code = " " + code + " "
+	}
+	return fence + code + fence
+}
+
+// textContent 返回节点的全部文本
+func textContent(node *html.Node) string {
+	if node.Type == html.TextNode {
+		return node.Data
+	}
+	var b strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		b.WriteString(textContent(child))
+	}
+	return b.String()
+}
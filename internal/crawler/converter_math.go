@@ -0,0 +1,149 @@
+package crawler
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// texAttrs 直接保存 TeX 源码的属性
+var texAttrs = []string{"data-tex", "data-latex", "data-formula"}
+
+// mathClasses 公式容器的类名（KaTeX、MathJax、维基百科、Pandoc/Sphinx、pymdownx.arithmatex）
+var mathClasses = map[string]bool{
+	"katex": true, "katex-display": true, "mathjax": true, "mathjax_display": true, "mathjax_chtml": true,
+	"mathjax_svg": true, "mwe-math-element": true, "math": true, "arithmatex": true,
+}
+
+// texDelimiterPattern 未经排版的公式文本中的 TeX 分隔符：\(...\)、\[...\]、$$...$$
+var texDelimiterPattern = regexp.MustCompile(`^\\\((?s:(.*))\\\)$|^\\\[(?s:(.*))\\\]$|^\$\$(?s:(.*))\$\$$`)
+
+// mathSource 从公式元素中恢复 TeX 源码，display 表示是否为独立公式
+//
+// 支持 MathJax 2 的 <script type="math/tex">、带 TeX 属性的元素，以及包含
+// <annotation encoding="application/x-tex"> 的 MathML、KaTeX 和 MathJax 渲染结果。
+func mathSource(node *html.Node) (tex string, display bool, ok bool) {
+	if node.Type != html.ElementNode {
+		return "", false, false
+	}
+
+	if node.Data == "script" {
+		scriptType := strings.ToLower(attrValue(node, "type"))
+		if !strings.HasPrefix(scriptType, "math/tex") {
+			return "", false, false
+		}
+		tex, display = textContent(node), strings.Contains(scriptType, "mode=display")
+	} else {
+		for _, name := range texAttrs {
+			if tex = attrValue(node, name); tex != "" {
+				break
+			}
+		}
+		if tex == "" {
+			if !isMathContainer(node) {
+				return "", false, false
+			}
+			annotation := findElement(node, func(n *html.Node) bool {
+				return n.Data == "annotation" && strings.Contains(strings.ToLower(attrValue(n, "encoding")), "tex")
+			})
+			if annotation != nil {
+				tex = textContent(annotation)
+			} else if match := texDelimiterPattern.FindStringSubmatch(strings.TrimSpace(textContent(node))); match != nil {
+				// 公式脚本没有运行时保留的原始文本
+				tex = match[1] + match[2] + match[3]
+				display = !strings.HasPrefix(match[0], `\(`)
+			} else {
+				return "", false, false
+			}
+		}
+		display = display || isDisplayMath(node)
+	}
+
+	tex = strings.TrimSpace(whitespacePattern.ReplaceAllString(tex, " "))
+	return tex, display, tex != ""
+}
+
+// isMathContainer 判断元素是否为 MathML 或公式渲染结果的容器（KaTeX、MathJax、维基百科等）
+func isMathContainer(node *html.Node) bool {
+	if node.Data == "math" || node.Data == "mjx-container" {
+		return true
+	}
+	for _, class := range strings.Fields(strings.ToLower(attrValue(node, "class"))) {
+		if mathClasses[class] {
+			return true
+		}
+	}
+	return false
+}
+
+// isDisplayMath 判断公式是否为独立公式：display 属性、类名中的 display，或 display="block" 的 MathML
+func isDisplayMath(node *html.Node) bool {
+	switch strings.ToLower(attrValue(node, "display")) {
+	case "true", "block":
+		return true
+	}
+	if strings.Contains(strings.ToLower(attrValue(node, "class")), "display") {
+		return true
+	}
+	return findElement(node, func(n *html.Node) bool {
+		return n.Data == "math" && strings.EqualFold(attrValue(n, "display"), "block")
+	}) != nil
+}
+
+// isMathJaxOutput 判断元素是否为 MathJax 2 的渲染结果（预览或排版后的公式），其 TeX 源码保存在随后的 script 中
+func isMathJaxOutput(node *html.Node) bool {
+	if !hasClassPrefix(node, "MathJax") {
+		return false
+	}
+	for sibling := node.NextSibling; sibling != nil; sibling = sibling.NextSibling {
+		if sibling.Type != html.ElementNode {
+			continue
+		}
+		if sibling.Data == "script" {
+			_, _, ok := mathSource(sibling)
+			return ok
+		}
+		if !hasClassPrefix(sibling, "MathJax") {
+			return false
+		}
+	}
+	return false
+}
+
+// mathMarkdown 将 TeX 源码渲染为 $...$ 或 $$...$$
+func mathMarkdown(tex string, display bool) string {
+	if display {
+		return "$$" + tex + "$$"
+	}
+	return "$" + tex + "$"
+}
+
+// hasClassPrefix 判断元素是否有以 prefix 开头的类名
+func hasClassPrefix(node *html.Node, prefix string) bool {
+	if node.Type != html.ElementNode {
+		return false
+	}
+	for _, class := range strings.Fields(attrValue(node, "class")) {
+		if strings.HasPrefix(class, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// findElement 返回第一个满足条件的后代元素
+func findElement(node *html.Node, match func(*html.Node) bool) *html.Node {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode {
+			continue
+		}
+		if match(child) {
+			return child
+		}
+		if found := findElement(child, match); found != nil {
+			return found
+		}
+	}
+	return nil
+}
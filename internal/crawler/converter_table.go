@@ -0,0 +1,371 @@
+package crawler
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// 表格无法用 GFM 表示（嵌套表格、单元格包含多个段落或列表等）时的回退方式
+const (
+	TableFallbackHTML = "html" // 原样输出表格的 HTML
+	TableFallbackList = "list" // 每一行渲染为一个列表项，单元格前加上列标题
+)
+
+// maxColspan colspan 的上限（与 HTML 规范一致）
+const maxColspan = 1000
+
+// structuralTags 单元格中出现时无法用 GFM 表格表示的元素
+var structuralTags = map[string]bool{
+	"table": true, "ul": true, "ol": true, "dl": true, "pre": true, "blockquote": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+var textAlignPattern = regexp.MustCompile(`(?i)text-align\s*:\s*(left|center|right)`)
+
+// tableCell 表格网格中的一格
+type tableCell struct {
+	node   *html.Node // 单元格元素；被 colspan/rowspan 覆盖的位置为 nil
+	header bool       // 是否为 th
+}
+
+// tableGrid 展开 colspan/rowspan 后的矩形表格
+type tableGrid struct {
+	caption *html.Node
+	rows    [][]*tableCell
+	header  bool // 第一行是否为表头
+}
+
+// SetTableFallback 设置表格无法用 GFM 表示时的回退方式（html 或 list）
+func (c *Converter) SetTableFallback(fallback string) error {
+	switch fallback {
+	case TableFallbackHTML, TableFallbackList:
+		c.tableFallback = fallback
+		return nil
+	}
+	return fmt.Errorf("unknown table fallback %q (want %s or %s)", fallback, TableFallbackHTML, TableFallbackList)
+}
+
+// renderTable 渲染表格：能用 GFM 表示时输出 GFM 表格，否则按回退方式输出
+//
+// alternate 为 true 时列表回退使用 * 标记；返回的 listed 表示输出是否为列表。
+func (c *Converter) renderTable(table *html.Node, alternate bool) (markdown string, listed bool) {
+	grid := parseTable(table)
+	if len(grid.rows) == 0 {
+		return "", false
+	}
+
+	if !c.representable(grid) {
+		if c.tableFallback == TableFallbackList {
+			return c.renderTableList(grid, alternate), true
+		}
+		return c.renderTableHTML(table), false
+	}
+
+	var lines []string
+	rows := grid.rows
+	if grid.header {
+		lines = append(lines, tableRow(c.tableCells(rows[0], true)))
+		rows = rows[1:]
+	} else {
+		// GFM 表格必须有表头，没有表头时使用空表头
+		lines = append(lines, tableRow(make([]string, len(rows[0]))))
+	}
+	lines = append(lines, tableRow(columnDelimiters(grid)))
+	for _, row := range rows {
+		lines = append(lines, tableRow(c.tableCells(row, false)))
+	}
+
+	markdown = strings.Join(lines, "\n")
+	if grid.caption != nil {
+		if caption := c.renderInline(grid.caption); caption != "" {
+			markdown = caption + "\n\n" + markdown
+		}
+	}
+	return markdown, false
+}
+
+// parseTable 读取表格自身的行（不含嵌套表格的行），按 colspan/rowspan 展开为矩形网格
+func parseTable(table *html.Node) *tableGrid {
+	grid := &tableGrid{}
+
+	// 表头行总是排在最前面
+	var head, body []*html.Node
+	for child := table.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode {
+			continue
+		}
+		switch child.Data {
+		case "caption":
+			if grid.caption == nil {
+				grid.caption = child
+			}
+		case "thead":
+			head = append(head, childElements(child, "tr")...)
+		case "tbody", "tfoot":
+			body = append(body, childElements(child, "tr")...)
+		case "tr":
+			body = append(body, child)
+		}
+	}
+	rows := append(head, body...)
+
+	cells := make([][]*tableCell, len(rows))
+	for r, tr := range rows {
+		col := 0
+		for _, td := range childElements(tr, "td", "th") {
+			for col < len(cells[r]) && cells[r][col] != nil {
+				col++
+			}
+			colspan := spanAttr(td, "colspan", maxColspan)
+			rowspan := spanAttr(td, "rowspan", len(rows)-r)
+			for dr := 0; dr < rowspan; dr++ {
+				for dc := 0; dc < colspan; dc++ {
+					cell := &tableCell{}
+					if dr == 0 && dc == 0 {
+						cell = &tableCell{node: td, header: td.Data == "th"}
+					}
+					setCell(&cells[r+dr], col+dc, cell)
+				}
+			}
+			col += colspan
+		}
+	}
+
+	width := 0
+	for _, row := range cells {
+		width = max(width, len(row))
+	}
+	for _, row := range cells {
+		if len(row) == 0 {
+			continue
+		}
+		for len(row) < width {
+			row = append(row, &tableCell{})
+		}
+		for i, cell := range row {
+			if cell == nil {
+				row[i] = &tableCell{}
+			}
+		}
+		grid.rows = append(grid.rows, row)
+	}
+
+	if len(grid.rows) > 0 {
+		grid.header = len(head) > 0 || isHeaderRow(grid.rows[0])
+	}
+	return grid
+}
+
+// representable 判断表格能否用 GFM 表格表示：每个单元格只能包含一个段落的内联内容
+func (c *Converter) representable(grid *tableGrid) bool {
+	for _, row := range grid.rows {
+		for _, cell := range row {
+			if cell.node != nil && (hasStructure(cell.node) || len(c.renderBlocks(cell.node)) > 1) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// tableCells 渲染一行单元格：换行输出为 <br>，表体中的 th 加粗，竖线转义
+func (c *Converter) tableCells(row []*tableCell, headerRow bool) []string {
+	cells := make([]string, len(row))
+	for i, cell := range row {
+		if cell.node == nil {
+			continue
+		}
+		text := strings.ReplaceAll(c.renderInline(cell.node), hardBreak, "<br>")
+		if cell.header && !headerRow && text != "" && !strings.HasPrefix(text, "**") {
+			text = "**" + text + "**"
+		}
+		cells[i] = escapeTableCell(text)
+	}
+	return cells
+}
+
+// columnDelimiters 返回分隔行：每列的对齐方式取该列第一个指定了对齐方式的单元格
+func columnDelimiters(grid *tableGrid) []string {
+	delimiters := make([]string, len(grid.rows[0]))
+	for col := range delimiters {
+		align := ""
+		for _, row := range grid.rows {
+			if row[col].node != nil {
+				if align = cellAlign(row[col].node); align != "" {
+					break
+				}
+			}
+		}
+		switch align {
+		case "left":
+			delimiters[col] = ":---"
+		case "center":
+			delimiters[col] = ":---:"
+		case "right":
+			delimiters[col] = "---:"
+		default:
+			delimiters[col] = "---"
+		}
+	}
+	return delimiters
+}
+
+// renderTableList 将表格渲染为列表：每行一项，有表头时单元格前加上列标题
+func (c *Converter) renderTableList(grid *tableGrid, alternate bool) string {
+	marker := "- "
+	if alternate {
+		marker = "* "
+	}
+
+	rows := grid.rows
+	var labels []string
+	if grid.header {
+		for _, cell := range rows[0] {
+			label := ""
+			if cell.node != nil {
+				label = strings.ReplaceAll(c.renderInline(cell.node), hardBreak, " ")
+			}
+			labels = append(labels, label)
+		}
+		rows = rows[1:]
+	}
+
+	var items []string
+	if grid.caption != nil {
+		if caption := c.renderInline(grid.caption); caption != "" {
+			items = append(items, caption)
+		}
+	}
+	for _, row := range rows {
+		var blocks []string
+		for col, cell := range row {
+			if cell.node == nil {
+				continue
+			}
+			content := c.renderBlocks(cell.node)
+			if len(content) == 0 {
+				continue
+			}
+			label := ""
+			if col < len(labels) {
+				label = labels[col]
+			}
+			switch {
+			case label == "":
+				blocks = append(blocks, content...)
+			case len(content) == 1 && !hasStructure(cell.node):
+				blocks = append(blocks, "**"+label+":** "+content[0])
+			default:
+				blocks = append(blocks, "**"+label+":**")
+				blocks = append(blocks, content...)
+			}
+		}
+		if len(blocks) > 0 {
+			items = append(items, marker+indentLines(strings.Join(blocks, "\n\n"), len(marker)))
+		}
+	}
+	return strings.Join(items, "\n\n")
+}
+
+// renderTableHTML 原样输出表格的 HTML（链接和图片改写为绝对地址）；去掉空行，使其在 Markdown 中保持为一个 HTML 块
+func (c *Converter) renderTableHTML(table *html.Node) string {
+	c.resolveElementURLs(table)
+	var b strings.Builder
+	if err := html.Render(&b, table); err != nil {
+		return ""
+	}
+	var lines []string
+	for _, line := range strings.Split(b.String(), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// tableRow 将单元格拼接为一行 GFM 表格
+func tableRow(cells []string) string {
+	return "| " + strings.Join(cells, " | ") + " |"
+}
+
+// isHeaderRow 判断一行的单元格是否都是 th
+func isHeaderRow(row []*tableCell) bool {
+	found := false
+	for _, cell := range row {
+		if cell.node == nil {
+			continue
+		}
+		if !cell.header {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// hasStructure 判断单元格是否包含无法放进一行的结构（嵌套表格、列表、代码块等）
+func hasStructure(node *html.Node) bool {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && (structuralTags[child.Data] || hasStructure(child)) {
+			return true
+		}
+	}
+	return false
+}
+
+// cellAlign 从 align 属性或 text-align 样式读取单元格的对齐方式
+func cellAlign(node *html.Node) string {
+	align := strings.ToLower(strings.TrimSpace(attrValue(node, "align")))
+	if align == "left" || align == "center" || align == "right" {
+		return align
+	}
+	if match := textAlignPattern.FindStringSubmatch(attrValue(node, "style")); match != nil {
+		return strings.ToLower(match[1])
+	}
+	return ""
+}
+
+// spanAttr 读取 colspan/rowspan，限制在 1 到 limit 之间；rowspan="0" 表示延伸到表格末尾
+func spanAttr(node *html.Node, name string, limit int) int {
+	n, err := strconv.Atoi(strings.TrimSpace(attrValue(node, name)))
+	switch {
+	case err != nil || n < 0:
+		return 1
+	case n == 0 && name == "rowspan":
+		return limit
+	case n == 0:
+		return 1
+	}
+	return min(n, limit)
+}
+
+// setCell 设置网格中一行的某一格，必要时扩展该行；已占用的格保持不变
+func setCell(row *[]*tableCell, col int, cell *tableCell) {
+	for len(*row) <= col {
+		*row = append(*row, nil)
+	}
+	if (*row)[col] == nil {
+		(*row)[col] = cell
+	}
+}
+
+// childElements 返回指定标签的直接子元素
+func childElements(node *html.Node, tags ...string) []*html.Node {
+	var elements []*html.Node
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode {
+			continue
+		}
+		for _, tag := range tags {
+			if child.Data == tag {
+				elements = append(elements, child)
+				break
+			}
+		}
+	}
+	return elements
+}
@@ -1,10 +1,24 @@
 package crawler
 
 import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	gmhtml "github.com/yuin/goldmark/renderer/html"
 )
 
+var updateGolden = flag.Bool("update", false, "rewrite the golden Markdown files in testdata/markdown")
+
+// mathCases 输出包含公式（$...$）的用例：CommonMark 和 GFM 都没有公式语法，不做重新解析验证
+var mathCases = map[string]bool{"math": true}
+
 func TestConverter_HTMLToMarkdown(t *testing.T) {
 	converter := NewConverter()
 
@@ -56,4 +70,193 @@ func TestConverter_HTMLToMarkdown(t *testing.T) {
 	}
 }
 
+func TestConverter_Golden(t *testing.T) {
+	// testdata/markdown 中每个 HTML 文件对应一个期望的 Markdown 文件（-update 重新生成）
+	pages, err := filepath.Glob(filepath.Join("testdata", "markdown", "*.html"))
+	if err != nil || len(pages) == 0 {
+		t.Fatalf("no golden cases found: %v", err)
+	}
+
+	converter := NewConverter()
+	for _, page := range pages {
+		name := strings.TrimSuffix(filepath.Base(page), ".html")
+		t.Run(name, func(t *testing.T) {
+			input, err := os.ReadFile(page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := converter.HTMLToMarkdown(string(input))
+			if err != nil {
+				t.Fatalf("HTMLToMarkdown() error = %v", err)
+			}
+
+			golden := strings.TrimSuffix(page, ".html") + ".md"
+			if *updateGolden {
+				if err := os.WriteFile(golden, []byte(got+"\n"), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != strings.TrimSuffix(string(want), "\n") {
+				t.Errorf("HTMLToMarkdown() mismatch for %s\n--- got ---\n%s\n--- want ---\n%s", name, got, want)
+			}
+			if !mathCases[name] {
+				assertReparses(t, converter, string(input), got)
+			}
+		})
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"plain text", "plain text"},
+		{"*star* and `tick`", `\*star\* and \` + "`" + `tick\` + "`"},
+		{"snake_case and _under_", `snake_case and \_under\_`},
+		{"a[0] ~x~", `a\[0\] \~x\~`},
+		{`C:\dir`, `C:\\dir`},
+		{"<b> and </i> and <!-- x", `\<b> and \</i> and \<!-- x`},
+		{"a < b && c", "a < b && c"},
+		{"&amp; &#169; &#x1F600; & x;", `\&amp; \&#169; \&#x1F600; & x;`},
+		{"a\x01b\x02c", "abc"},
+	}
+	for _, tt := range tests {
+		if got := escapeText(tt.text); got != tt.want {
+			t.Errorf("escapeText(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
 
+func TestEscapeLineStart(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"# title", `\# title`},
+		{"#hashtag", "#hashtag"},
+		{"####### seven", "####### seven"},
+		{"- item", `\- item`},
+		{"-5 degrees", "-5 degrees"},
+		{"+ plus", `\+ plus`},
+		{"> quote", `\> quote`},
+		{"2024. A year", `2024\. A year`},
+		{"3) third", `3\) third`},
+		{"3.14 is pi", "3.14 is pi"},
+		{"---", `\---`},
+		{"==", `\==`},
+	}
+	for _, tt := range tests {
+		if got := escapeLineStart(tt.line); got != tt.want {
+			t.Errorf("escapeLineStart(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestLinkDestination(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://example.com/a?b=c", "https://example.com/a?b=c"},
+		{"/my page", "/my%20page"},
+		{"/wiki/Go_(language)", "</wiki/Go_(language)>"},
+		{"/a<b>", `</a\<b\>>`},
+		{`/a\b`, "/a%5Cb"},
+	}
+	for _, tt := range tests {
+		if got := linkDestination(tt.url); got != tt.want {
+			t.Errorf("linkDestination(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+// assertReparses 用 CommonMark 和 GFM 解析器重新解析生成的 Markdown：
+// 渲染回 HTML 后再次转换应得到相同的 Markdown（结构一致），且文本与原 HTML 一致（没有多余或丢失的转义）
+func assertReparses(t *testing.T, converter *Converter, source string, markdown string) {
+	t.Helper()
+
+	parsers := map[string]goldmark.Markdown{
+		// 不启用 Linkify：它会把纯文本网址变成链接，属于有意的渲染差异；定义列表不属于 GFM，但常见的渲染器都支持
+		"gfm": goldmark.New(
+			goldmark.WithExtensions(extension.Table, extension.Strikethrough, extension.TaskList, extension.DefinitionList),
+			goldmark.WithRendererOptions(gmhtml.WithUnsafe()),
+		),
+	}
+	// CommonMark 不支持表格、删除线和定义列表，只验证不含这些元素的输出
+	extended := false
+	for _, tag := range []string{"<table", "<del", "<s>", "<strike", "<dl"} {
+		extended = extended || strings.Contains(source, tag)
+	}
+	if !extended {
+		parsers["commonmark"] = goldmark.New(goldmark.WithRendererOptions(gmhtml.WithUnsafe()))
+	}
+
+	for name, parser := range parsers {
+		var rendered bytes.Buffer
+		if err := parser.Convert([]byte(markdown), &rendered); err != nil {
+			t.Fatalf("%s: Convert() error = %v", name, err)
+		}
+
+		reparsed, err := converter.HTMLToMarkdown(rendered.String())
+		if err != nil {
+			t.Fatalf("%s: HTMLToMarkdown() error = %v", name, err)
+		}
+		if reparsed != markdown {
+			t.Errorf("%s: Markdown does not re-parse to the same structure\n--- markdown ---\n%s\n--- rendered ---\n%s\n--- reconverted ---\n%s", name, markdown, rendered.String(), reparsed)
+		}
+		if want, got := visibleText(t, source), visibleText(t, rendered.String()); got != want {
+			t.Errorf("%s: rendered text differs from the source\n--- source ---\n%s\n--- rendered ---\n%s", name, want, got)
+		}
+	}
+}
+
+// visibleText 返回 HTML 中可见的文本（去掉所有空白）
+func visibleText(t *testing.T, rawHTML string) string {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(rawHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.Find("script, style, noscript, template").Remove()
+	return strings.Join(strings.Fields(doc.Text()), "")
+}
+
+func TestConverter_TableListFallback(t *testing.T) {
+	input, err := os.ReadFile(filepath.Join("testdata", "markdown", "table_fallback.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	converter := NewConverter()
+	if err := converter.SetTableFallback(TableFallbackList); err != nil {
+		t.Fatalf("SetTableFallback() error = %v", err)
+	}
+
+	got, err := converter.HTMLToMarkdown(string(input) + "<ul><li>After</li></ul>")
+	if err != nil {
+		t.Fatalf("HTMLToMarkdown() error = %v", err)
+	}
+	want := "Before the table.\n\n" +
+		"- **Option:** `-depth`\n\n" +
+		"  **Description:**\n\n" +
+		"  Maximum crawl depth.\n\n" +
+		"  Zero crawls only the start page.\n\n" +
+		"- **Option:** `-sort`\n\n" +
+		"  **Description:**\n\n" +
+		"  |  |  |\n" +
+		"  | --- | --- |\n" +
+		"  | crawl | rank |\n\n" +
+		"* After"
+	if got != want {
+		t.Errorf("HTMLToMarkdown() = \n%s\nwant\n%s", got, want)
+	}
+
+	if err := converter.SetTableFallback("csv"); err == nil {
+		t.Error("expected an error for an unknown table fallback")
+	}
+}
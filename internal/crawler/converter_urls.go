@@ -0,0 +1,256 @@
+package crawler
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// lazySourceAttrs 懒加载图片保存真实地址的属性，按优先级排列
+var lazySourceAttrs = []string{"data-src", "data-original", "data-lazy-src", "data-url"}
+
+// lazySrcsetAttrs 保存响应式图片地址的属性，按优先级排列
+var lazySrcsetAttrs = []string{"data-srcset", "srcset"}
+
+// placeholderPattern 懒加载使用的占位图
+var placeholderPattern = regexp.MustCompile(`(?i)^data:|(?:^|/)(?:blank|spacer|pixel|transparent|placeholder)\.(?:gif|png|svg|jpe?g|webp)(?:$|\?)`)
+
+// PageToMarkdown 将页面正文转换为 Markdown，链接和图片地址解析为相对于 baseURL 的绝对地址
+func (c *Converter) PageToMarkdown(rawHTML string, baseURL string) (string, error) {
+	page := *c
+	if base, err := url.Parse(baseURL); err == nil && base.IsAbs() {
+		page.base = base
+	}
+	return page.HTMLToMarkdown(rawHTML)
+}
+
+// resolveURL 将地址解析为相对于页面的绝对地址；没有页面地址或无法解析时保持原样
+func (c *Converter) resolveURL(ref string) string {
+	if c.base == nil {
+		return ref
+	}
+	parsed, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return c.base.ResolveReference(parsed).String()
+}
+
+// linkHref 返回链接的绝对地址；没有地址或为 javascript: 时返回空字符串
+func (c *Converter) linkHref(node *html.Node) string {
+	href := strings.TrimSpace(attrValue(node, "href"))
+	if href == "" || strings.HasPrefix(strings.ToLower(href), "javascript:") {
+		return ""
+	}
+	return c.resolveURL(href)
+}
+
+// imageSource 返回图片的绝对地址：懒加载属性优先，src 为占位图时使用 srcset 中最大的图片
+func (c *Converter) imageSource(node *html.Node) string {
+	for _, name := range lazySourceAttrs {
+		if src := strings.TrimSpace(attrValue(node, name)); src != "" && !placeholderPattern.MatchString(src) {
+			return c.resolveURL(src)
+		}
+	}
+	if src := strings.TrimSpace(attrValue(node, "src")); src != "" && !placeholderPattern.MatchString(src) {
+		return c.resolveURL(src)
+	}
+
+	// <picture> 中的 <source> 也可以提供 srcset
+	candidates := []*html.Node{node}
+	if node.Parent != nil && node.Parent.Type == html.ElementNode && node.Parent.Data == "picture" {
+		candidates = append(candidates, childElements(node.Parent, "source")...)
+	}
+	for _, candidate := range candidates {
+		for _, name := range lazySrcsetAttrs {
+			if src := largestSrcsetImage(attrValue(candidate, name)); src != "" {
+				return c.resolveURL(src)
+			}
+		}
+	}
+	return ""
+}
+
+// largestSrcsetImage 返回 srcset 中宽度（或像素密度）最大的图片地址
+func largestSrcsetImage(srcset string) string {
+	best, bestWidth, bestDensity := "", -1.0, -1.0
+	const spaces = " \t\n\r\f"
+	for rest := srcset; ; {
+		// 地址到空白为止（地址中可以有逗号，如 data: URI），以逗号结尾时没有描述
+		rest = strings.TrimLeft(rest, spaces+",")
+		if rest == "" {
+			break
+		}
+		end := strings.IndexAny(rest, spaces)
+		if end < 0 {
+			end = len(rest)
+		}
+		src, descriptor := rest[:end], ""
+		rest = rest[end:]
+		if trimmed := strings.TrimRight(src, ","); trimmed != src {
+			src = trimmed
+		} else {
+			end = strings.IndexByte(rest, ',')
+			if end < 0 {
+				end = len(rest)
+			}
+			descriptor = strings.ToLower(strings.TrimSpace(rest[:end]))
+			rest = rest[end:]
+		}
+		if placeholderPattern.MatchString(src) {
+			continue
+		}
+
+		width, density := 0.0, 1.0
+		if descriptor != "" {
+			value, err := strconv.ParseFloat(descriptor[:len(descriptor)-1], 64)
+			switch {
+			case err != nil:
+			case strings.HasSuffix(descriptor, "w"):
+				width = value
+			case strings.HasSuffix(descriptor, "x"):
+				density = value
+			}
+		}
+		// 有宽度描述的候选优先按宽度比较，否则按像素密度比较
+		if width > bestWidth || width == bestWidth && density > bestDensity {
+			best, bestWidth, bestDensity = src, width, density
+		}
+	}
+	return best
+}
+
+// resolveElementURLs 将元素及其后代中的链接和图片地址改写为绝对地址（用于原样输出的 HTML）
+func (c *Converter) resolveElementURLs(node *html.Node) {
+	if node.Type == html.ElementNode {
+		switch node.Data {
+		case "a":
+			if href := c.linkHref(node); href != "" {
+				setAttr(node, "href", href)
+			}
+		case "img":
+			if src := c.imageSource(node); src != "" {
+				setAttr(node, "src", src)
+			}
+		}
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		c.resolveElementURLs(child)
+	}
+}
+
+// setAttr 设置元素的属性值
+func setAttr(node *html.Node, name string, value string) {
+	for i := range node.Attr {
+		if node.Attr[i].Key == name {
+			node.Attr[i].Val = value
+			return
+		}
+	}
+	node.Attr = append(node.Attr, html.Attribute{Key: name, Val: value})
+}
+
+// RewriteMarkdownLinks 改写转换器生成的 Markdown 中的链接地址（不含图片地址）
+//
+// rewrite 返回 false 时保持原地址；代码块和行内代码中的内容不会被改写。
+func RewriteMarkdownLinks(markdown string, rewrite func(dest string) (string, bool)) string {
+	var b strings.Builder
+	fence := ""
+	for _, line := range strings.SplitAfter(markdown, "\n") {
+		// 代码块可能位于列表（缩进）或引用（>）中
+		content := strings.TrimRight(strings.TrimLeft(line, " >"), "\n")
+		switch {
+		case fence != "":
+			if strings.HasPrefix(content, fence) && strings.Trim(content, "`") == "" {
+				fence = ""
+			}
+			b.WriteString(line)
+		case strings.HasPrefix(content, "```"):
+			fence = strings.Repeat("`", longestRun(content, '`'))
+			b.WriteString(line)
+		default:
+			b.WriteString(rewriteLineLinks(line, rewrite))
+		}
+	}
+	return b.String()
+}
+
+// rewriteLineLinks 改写一行中的链接地址，跳过转义字符和行内代码
+func rewriteLineLinks(line string, rewrite func(dest string) (string, bool)) string {
+	var b strings.Builder
+	var images []bool // 未闭合的 [ 是否属于图片
+	bang := false     // 前一个字符是否为未转义的 !
+	for i := 0; i < len(line); {
+		image := bang
+		bang = false
+		switch {
+		case line[i] == '\\' && i+1 < len(line):
+			b.WriteString(line[i : i+2])
+			i += 2
+		case line[i] == '`':
+			run := len(line[i:]) - len(strings.TrimLeft(line[i:], "`"))
+			fence := strings.Repeat("`", run)
+			end := strings.Index(line[i+run:], fence)
+			if end < 0 {
+				b.WriteString(fence)
+				i += run
+				continue
+			}
+			b.WriteString(line[i : i+run+end+run])
+			i += run + end + run
+		case line[i] == '[':
+			images = append(images, image)
+			b.WriteByte('[')
+			i++
+		case line[i] == ']' && strings.HasPrefix(line[i:], "](") && len(images) > 0:
+			image = images[len(images)-1]
+			images = images[:len(images)-1]
+			dest, end, ok := parseLinkDestination(line, i+2)
+			if !ok {
+				b.WriteByte(']')
+				i++
+				continue
+			}
+			replacement := line[i:end]
+			if !image {
+				if rewritten, ok := rewrite(dest); ok {
+					replacement = "](" + linkDestination(rewritten) + ")"
+				}
+			}
+			b.WriteString(replacement)
+			i = end
+		default:
+			bang = line[i] == '!'
+			b.WriteByte(line[i])
+			i++
+		}
+	}
+	return b.String()
+}
+
+// parseLinkDestination 解析从 start 开始的链接地址（<...> 或不含空格的地址），返回地址和 ) 之后的位置
+func parseLinkDestination(line string, start int) (string, int, bool) {
+	if start < len(line) && line[start] == '<' {
+		for i := start + 1; i < len(line); i++ {
+			switch line[i] {
+			case '\\':
+				i++
+			case '>':
+				if i+1 < len(line) && line[i+1] == ')' {
+					dest := strings.NewReplacer(`\<`, "<", `\>`, ">").Replace(line[start+1 : i])
+					return dest, i + 2, true
+				}
+				return "", 0, false
+			}
+		}
+		return "", 0, false
+	}
+	end := strings.IndexAny(line[start:], ") \n")
+	if end < 0 || line[start+end] != ')' {
+		return "", 0, false
+	}
+	return line[start : start+end], start + end + 1, true
+}
@@ -0,0 +1,101 @@
+package crawler
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConverter_PageToMarkdown(t *testing.T) {
+	converter := NewConverter()
+	input := `
+		<p><a href="../api.html">API</a>, <a href="#usage">usage</a>, <a href="mailto:team@example.com">mail</a> and <a href="javascript:void(0)">menu</a>.</p>
+		<p><img src="data:image/gif;base64,R0lGODlhAQABAAAAACw=" data-src="img/photo.png" alt="Lazy"></p>
+		<p><img src="/img/blank.gif" srcset="img/small.png 480w, img/large.png 1200w, img/medium.png 800w" alt="Responsive"></p>
+		<p><picture><source srcset="img/pic.webp 1x, img/pic@2x.webp 2x"><img src="data:image/svg+xml,%3Csvg%3E" alt="Picture"></picture></p>
+		<p><img src="data:image/png;base64,AAAA" alt="Placeholder only"></p>
+		<table><tr><td><p>One</p><p><a href="guide.html">Two</a></p></td></tr></table>
+	`
+	got, err := converter.PageToMarkdown(input, "https://example.com/docs/v1/")
+	if err != nil {
+		t.Fatalf("PageToMarkdown() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"[API](https://example.com/docs/api.html)",
+		"[usage](https://example.com/docs/v1/#usage)",
+		"[mail](mailto:team@example.com)",
+		"and menu.",
+		"![Lazy](https://example.com/docs/v1/img/photo.png)",
+		"![Responsive](https://example.com/docs/v1/img/large.png)",
+		"![Picture](https://example.com/docs/v1/img/pic@2x.webp)",
+		`<a href="https://example.com/docs/v1/guide.html">Two</a>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "data:") || strings.Contains(got, "Placeholder only") {
+		t.Errorf("placeholder images should be skipped:\n%s", got)
+	}
+
+	// 没有页面地址时保持原地址
+	if got, _ := converter.HTMLToMarkdown(`<a href="../api.html">API</a>`); got != "[API](../api.html)" {
+		t.Errorf("HTMLToMarkdown() = %q, want the raw href", got)
+	}
+}
+
+func TestRewriteMarkdownLinks(t *testing.T) {
+	markdown := strings.Join([]string{
+		"See [the guide](https://example.com/guide#setup) and [elsewhere](https://other.example.com/).",
+		"[![Logo](https://example.com/logo.png)](https://example.com/guide)",
+		"Not a link: \\[x\\](https://example.com/guide) or `[y](https://example.com/guide)`.",
+		"- [Spaced](</a (b)>)",
+		"",
+		"```",
+		"[z](https://example.com/guide)",
+		"```",
+	}, "\n")
+
+	got := RewriteMarkdownLinks(markdown, func(dest string) (string, bool) {
+		switch strings.SplitN(dest, "#", 2)[0] {
+		case "https://example.com/guide":
+			return strings.Replace(dest, "https://example.com/guide", "guide.md", 1), true
+		case "/a (b)":
+			return "a b.md", true
+		}
+		return "", false
+	})
+
+	want := strings.Join([]string{
+		"See [the guide](guide.md#setup) and [elsewhere](https://other.example.com/).",
+		"[![Logo](https://example.com/logo.png)](guide.md)",
+		"Not a link: \\[x\\](https://example.com/guide) or `[y](https://example.com/guide)`.",
+		"- [Spaced](a%20b.md)",
+		"",
+		"```",
+		"[z](https://example.com/guide)",
+		"```",
+	}, "\n")
+	if got != want {
+		t.Errorf("RewriteMarkdownLinks() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestLargestSrcsetImage(t *testing.T) {
+	tests := []struct {
+		srcset string
+		want   string
+	}{
+		{"a.png 1x, b.png 2x", "b.png"},
+		{"a.png 300w, b.png 1000w, c.png 600w", "b.png"},
+		{"only.png", "only.png"},
+		{"data:image/gif;base64,AAAA 1x, real.png 1x", "real.png"},
+		{"a.png, b.png 2x,", "b.png"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := largestSrcsetImage(tt.srcset); got != tt.want {
+			t.Errorf("largestSrcsetImage(%q) = %q, want %q", tt.srcset, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package crawler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"flaremind/internal/profile"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// debugIDAttr 调试模式下为每个元素编号的属性，用于将调试信息与标注 HTML 中的元素对应
+const debugIDAttr = "data-flaremind-id"
+
+// debugRoleAttr 标注 HTML 中标记元素角色（chosen、merged、candidate、removed）的属性
+const debugRoleAttr = "data-flaremind-debug"
+
+// debugStyle 标注 HTML 中高亮各类元素的样式
+const debugStyle = `<style>
+[data-flaremind-debug=chosen]{outline:3px solid #2e7d32 !important;background:rgba(46,125,50,.08) !important}
+[data-flaremind-debug=merged]{outline:2px solid #66bb6a !important}
+[data-flaremind-debug=candidate]{outline:1px dashed #1565c0 !important}
+[data-flaremind-debug=removed]{outline:2px solid #c62828 !important;opacity:.5}
+#flaremind-debug-legend{position:fixed;right:8px;bottom:8px;z-index:2147483647;padding:6px 10px;background:#fff;border:1px solid #999;font:12px sans-serif;color:#000}
+</style>`
+
+// ExtractionDebug 一次正文提取的调试信息：各次尝试考虑的候选块、评分因素和被移除的元素
+type ExtractionDebug struct {
+	URL      string            `json:"url"`
+	Profile  string            `json:"profile,omitempty"`  // 使用的站点配置
+	Selected int               `json:"selected"`           // 最终采用的尝试在 Attempts 中的下标（-1 表示没有找到正文）
+	Removed  []*RemovedElement `json:"removed,omitempty"`  // 站点配置的 remove 选择器移除的元素
+	Attempts []*AttemptDebug   `json:"attempts,omitempty"` // 依次进行的提取尝试
+
+	source string // 带元素编号的原始 HTML
+}
+
+// AttemptDebug 单次提取尝试
+type AttemptDebug struct {
+	Method     string            `json:"method"`            // readability、heuristic、body 或 profile
+	Options    *AttemptOptions   `json:"options,omitempty"` // Readability 尝试的清理选项
+	TextLength int               `json:"text_length"`       // 提取结果的字符数
+	Chosen     *ElementRef       `json:"chosen,omitempty"`  // 选中的正文块
+	Merged     []*ElementRef     `json:"merged,omitempty"`  // 与选中块合并的兄弟节点或其他正文根元素
+	Candidates []*CandidateDebug `json:"candidates,omitempty"`
+	Removed    []*RemovedElement `json:"removed,omitempty"`
+}
+
+// AttemptOptions Readability 尝试的清理选项
+type AttemptOptions struct {
+	StripUnlikely      bool `json:"strip_unlikely"`
+	WeightClasses      bool `json:"weight_classes"`
+	CleanConditionally bool `json:"clean_conditionally"`
+}
+
+// ElementRef 文档中的元素
+type ElementRef struct {
+	ID   int    `json:"id"`   // 与标注 HTML 中的 data-flaremind-id 对应
+	Path string `json:"path"` // 如 body > div#main.content > article
+}
+
+// CandidateDebug 候选块及其分数
+type CandidateDebug struct {
+	ElementRef
+	Score   float64      `json:"score"`
+	Factors ScoreFactors `json:"factors"`
+}
+
+// ScoreFactors 候选块分数的组成
+type ScoreFactors struct {
+	TagScore       float64 `json:"tag_score"`       // 按标签给出的初始分数
+	ClassWeight    float64 `json:"class_weight"`    // class/id 的加分或减分
+	ParagraphScore float64 `json:"paragraph_score"` // 从段落传播来的分数
+	Paragraphs     int     `json:"paragraphs"`      // 段落数量
+	TextLength     int     `json:"text_length"`     // 文本字符数
+	LinkDensity    float64 `json:"link_density"`    // 链接文本占比（分数乘以 1 - 链接密度）
+}
+
+// RemovedElement 被移除的元素
+type RemovedElement struct {
+	ElementRef
+	Reason     string `json:"reason"` // noise、unlikely、conditional 或 profile
+	TextLength int    `json:"text_length"`
+}
+
+// ExplainExtraction 按与 ExtractMainContentWithProfile 相同的流程提取正文，并返回调试信息
+func (e *Extractor) ExplainExtraction(rawHTML string, p *profile.Profile) (*ExtractionDebug, error) {
+	return explain(rawHTML, func(source string, debug *ExtractionDebug) error {
+		_, err := e.extractWithProfile(source, p, debug)
+		return err
+	})
+}
+
+// explain 为页面中的每个元素编号后运行提取过程 run，并返回记录的调试信息
+func explain(rawHTML string, run func(source string, debug *ExtractionDebug) error) (*ExtractionDebug, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(rawHTML))
+	if err != nil {
+		return nil, err
+	}
+	doc.Find("*").Each(func(i int, s *goquery.Selection) {
+		s.SetAttr(debugIDAttr, strconv.Itoa(i+1))
+	})
+	source, err := doc.Html()
+	if err != nil {
+		return nil, err
+	}
+
+	debug := &ExtractionDebug{Selected: -1, source: source}
+	if err := run(source, debug); err != nil {
+		return nil, err
+	}
+	return debug, nil
+}
+
+// AnnotatedHTML 返回标注后的页面 HTML：高亮选中的正文块、合并的节点、其他候选块和被移除的元素
+// 脚本会被移除，以便离线查看
+func (d *ExtractionDebug) AnnotatedHTML() (string, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(d.source))
+	if err != nil {
+		return "", err
+	}
+	doc.Find("script").Remove()
+
+	elements := make(map[int]*goquery.Selection)
+	doc.Find("[" + debugIDAttr + "]").Each(func(i int, s *goquery.Selection) {
+		if id, err := strconv.Atoi(s.AttrOr(debugIDAttr, "")); err == nil {
+			elements[id] = s
+		}
+	})
+	mark := func(ref *ElementRef, role string, title string) {
+		if ref == nil || elements[ref.ID] == nil {
+			return
+		}
+		elements[ref.ID].SetAttr(debugRoleAttr, role).SetAttr("title", title)
+	}
+
+	for _, removed := range d.Removed {
+		mark(&removed.ElementRef, "removed", "removed: "+removed.Reason)
+	}
+	if d.Selected >= 0 && d.Selected < len(d.Attempts) {
+		attempt := d.Attempts[d.Selected]
+		for _, removed := range attempt.Removed {
+			mark(&removed.ElementRef, "removed", "removed: "+removed.Reason)
+		}
+		for _, candidate := range attempt.Candidates {
+			mark(&candidate.ElementRef, "candidate", candidate.describe())
+		}
+		for _, merged := range attempt.Merged {
+			mark(merged, "merged", "merged with the chosen block")
+		}
+		title := "chosen"
+		for _, candidate := range attempt.Candidates {
+			if attempt.Chosen != nil && candidate.ID == attempt.Chosen.ID {
+				title = "chosen, " + candidate.describe()
+			}
+		}
+		mark(attempt.Chosen, "chosen", title)
+	}
+
+	doc.Find("head").AppendHtml(debugStyle)
+	doc.Find("body").AppendHtml(`<div id="flaremind-debug-legend">` +
+		`<span style="color:#2e7d32">chosen</span> · <span style="color:#66bb6a">merged</span> · ` +
+		`<span style="color:#1565c0">candidate</span> · <span style="color:#c62828">removed</span></div>`)
+	return doc.Html()
+}
+
+// describe 返回候选块分数的简短说明
+func (c *CandidateDebug) describe() string {
+	f := c.Factors
+	return fmt.Sprintf("score %.1f (tag %.0f, class %.0f, %d paragraphs %.1f, %d chars, link density %.2f)",
+		c.Score, f.TagScore, f.ClassWeight, f.Paragraphs, f.ParagraphScore, f.TextLength, f.LinkDensity)
+}
+
+// newAttempt 开始记录一次提取尝试（d 为 nil 时返回 nil）
+func (d *ExtractionDebug) newAttempt(method string, options *readabilityOptions) *AttemptDebug {
+	if d == nil {
+		return nil
+	}
+	attempt := &AttemptDebug{Method: method}
+	if options != nil {
+		attempt.Options = &AttemptOptions{
+			StripUnlikely:      options.stripUnlikely,
+			WeightClasses:      options.weightClasses,
+			CleanConditionally: options.cleanConditionally,
+		}
+	}
+	d.Attempts = append(d.Attempts, attempt)
+	return attempt
+}
+
+// selectAttempt 记录最终采用的尝试
+func (d *ExtractionDebug) selectAttempt(attempt *AttemptDebug) {
+	if d == nil {
+		return
+	}
+	for i, a := range d.Attempts {
+		if a == attempt {
+			d.Selected = i
+		}
+	}
+}
+
+// recordRemoved 记录站点配置移除的元素
+func (d *ExtractionDebug) recordRemoved(s *goquery.Selection, reason string) {
+	if d == nil {
+		return
+	}
+	d.Removed = append(d.Removed, removedElements(s, reason)...)
+}
+
+// recordRemoved 记录本次尝试移除的元素
+func (a *AttemptDebug) recordRemoved(s *goquery.Selection, reason string) {
+	if a == nil {
+		return
+	}
+	a.Removed = append(a.Removed, removedElements(s, reason)...)
+}
+
+// recordCandidates 按分数从高到低记录候选块
+func (a *AttemptDebug) recordCandidates(candidates []*html.Node, scores map[*html.Node]float64, factors map[*html.Node]*ScoreFactors) {
+	if a == nil {
+		return
+	}
+	for _, candidate := range candidates {
+		a.recordCandidate(candidate, scores[candidate], *factors[candidate])
+	}
+}
+
+// recordCandidate 记录一个候选块
+func (a *AttemptDebug) recordCandidate(node *html.Node, score float64, factors ScoreFactors) {
+	if a == nil || node == nil {
+		return
+	}
+	a.Candidates = append(a.Candidates, &CandidateDebug{ElementRef: *elementRef(node), Score: score, Factors: factors})
+}
+
+// recordChosen 记录选中的正文块及与其合并的节点
+func (a *AttemptDebug) recordChosen(chosen *html.Node, parts []*html.Node) {
+	if a == nil || chosen == nil {
+		return
+	}
+	a.Chosen = elementRef(chosen)
+	for _, part := range parts {
+		if part != chosen {
+			a.Merged = append(a.Merged, elementRef(part))
+		}
+	}
+}
+
+// removedElements 将选择集转换为被移除元素的记录，跳过已脱离文档或祖先也在选择集中的元素
+func removedElements(s *goquery.Selection, reason string) []*RemovedElement {
+	selected := make(map[*html.Node]bool, len(s.Nodes))
+	for _, node := range s.Nodes {
+		selected[node] = true
+	}
+
+	var removed []*RemovedElement
+	for _, node := range s.Nodes {
+		attached, nested := false, false
+		for parent := node.Parent; parent != nil; parent = parent.Parent {
+			if selected[parent] {
+				nested = true
+			}
+			if parent.Type == html.DocumentNode {
+				attached = true
+			}
+		}
+		if !attached || nested {
+			continue
+		}
+		removed = append(removed, &RemovedElement{
+			ElementRef: *elementRef(node),
+			Reason:     reason,
+			TextLength: textLength(goquery.NewDocumentFromNode(node).Text()),
+		})
+	}
+	return removed
+}
+
+// elementRef 返回元素的编号和可读路径
+func elementRef(node *html.Node) *ElementRef {
+	id, _ := strconv.Atoi(attrValue(node, debugIDAttr))
+
+	var path []string
+	for n := node; n != nil && n.Type == html.ElementNode && n.Data != "html"; n = n.Parent {
+		step := n.Data
+		if value := attrValue(n, "id"); value != "" {
+			step += "#" + value
+		}
+		classes := strings.Fields(attrValue(n, "class"))
+		if len(classes) > 2 {
+			classes = classes[:2]
+		}
+		for _, class := range classes {
+			step += "." + class
+		}
+		path = append([]string{step}, path...)
+	}
+	return &ElementRef{ID: id, Path: strings.Join(path, " > ")}
+}
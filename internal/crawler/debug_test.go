@@ -0,0 +1,91 @@
+package crawler
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestExtractor_ExplainExtraction(t *testing.T) {
+	extractor := NewExtractor()
+	html := `
+		<html>
+			<head><script>console.log("tracking")</script></head>
+			<body>
+				<nav class="menu"><a href="/">Home</a><a href="/about">About</a></nav>
+				<div id="main" class="content">
+					<p>The first paragraph of the article explains the topic in detail, with commas, clauses, and enough text to score.</p>
+					<p>The second paragraph continues the explanation, adding more context, examples, and another long sentence here.</p>
+					<p>The third paragraph wraps up the article, summarising the points, and linking back to the introduction text.</p>
+				</div>
+				<div class="related-links"><a href="/1">Related one</a> <a href="/2">Related two</a></div>
+			</body>
+		</html>
+	`
+
+	debug, err := extractor.ExplainExtraction(html, nil)
+	if err != nil {
+		t.Fatalf("ExplainExtraction() error = %v", err)
+	}
+	if debug.Selected < 0 {
+		t.Fatalf("expected a selected attempt, got %+v", debug)
+	}
+
+	attempt := debug.Attempts[debug.Selected]
+	if attempt.Method != "readability" || attempt.Chosen == nil || attempt.Chosen.Path != "body > div#main.content" {
+		t.Fatalf("chosen = %+v, want body > div#main.content", attempt.Chosen)
+	}
+	if len(attempt.Candidates) == 0 {
+		t.Fatal("expected scored candidates")
+	}
+	top := attempt.Candidates[0]
+	if top.ID != attempt.Chosen.ID || top.Factors.ClassWeight != 50 || top.Factors.Paragraphs != 3 || top.Factors.ParagraphScore == 0 {
+		t.Errorf("top candidate = %+v, want the chosen block with class and id bonuses and 3 paragraphs", top)
+	}
+
+	reasons := make(map[string]bool)
+	for _, removed := range attempt.Removed {
+		reasons[removed.Path+":"+removed.Reason] = true
+	}
+	if !reasons["body > nav.menu:noise"] || !reasons["body > div.related-links:unlikely"] {
+		t.Errorf("removed = %v, want the menu as noise and related links as unlikely", reasons)
+	}
+
+	if _, err := json.Marshal(debug); err != nil {
+		t.Errorf("json.Marshal() error = %v", err)
+	}
+
+	annotated, err := debug.AnnotatedHTML()
+	if err != nil {
+		t.Fatalf("AnnotatedHTML() error = %v", err)
+	}
+	if !strings.Contains(annotated, `id="main" class="content" data-flaremind-id="`) ||
+		!strings.Contains(annotated, `data-flaremind-debug="chosen"`) ||
+		!strings.Contains(annotated, `data-flaremind-debug="removed"`) {
+		t.Errorf("expected the chosen and removed blocks to be annotated, got %s", annotated)
+	}
+	if strings.Contains(annotated, "tracking") {
+		t.Error("expected scripts to be stripped from the annotated HTML")
+	}
+}
+
+func TestExtractor_ExplainExtractionWithProfile(t *testing.T) {
+	extractor := NewExtractor()
+	p := mustParseProfile(t, "name: forum\nhosts: [forum.example.com]\ncontent: ['.post-body']\nremove: ['.signature']\n")
+	html := `<html><body>
+		<div class="post-body"><p>First post.</p><p class="signature">Sent from my phone</p></div>
+		<div class="post-body"><p>Second post.</p></div>
+	</body></html>`
+
+	debug, err := extractor.ExplainExtraction(html, p)
+	if err != nil {
+		t.Fatalf("ExplainExtraction() error = %v", err)
+	}
+	if debug.Profile != "forum" || len(debug.Removed) != 1 || debug.Removed[0].Reason != "profile" {
+		t.Errorf("debug = %+v, want the signature removed by the forum profile", debug)
+	}
+	attempt := debug.Attempts[debug.Selected]
+	if attempt.Method != "profile" || attempt.Chosen == nil || len(attempt.Merged) != 1 {
+		t.Errorf("attempt = %+v, want both post bodies chosen by the profile", attempt)
+	}
+}
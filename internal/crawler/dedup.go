@@ -0,0 +1,91 @@
+package crawler
+
+import (
+	"flaremind/internal/models"
+	"flaremind/pkg/utils"
+)
+
+// GroupNearDuplicates 根据 SimHash 指纹对近似重复页面分组
+// 页面按爬取顺序与已有各组的代表页面比较，归入第一个相似度不低于 threshold 的组，否则作为新组的代表；
+// 只与代表比较，A 与 B、B 与 C 相似时不会把不相似的 A 和 C 传递地归为一组。
+// 非代表页面的 NearDuplicateOf 指向代表页面。dropDuplicates 为 true 时从结果中移除非代表页面
+func GroupNearDuplicates(results []models.PageResult, threshold float64, dropDuplicates bool) []models.PageResult {
+	if threshold <= 0 {
+		return results
+	}
+
+	// 解析指纹（没有指纹的页面不参与分组）
+	fingerprints := make([]uint64, len(results))
+	valid := make([]bool, len(results))
+	for i, result := range results {
+		if result.Fingerprint == "" || result.DuplicateOf != "" {
+			continue
+		}
+		fingerprint, err := utils.ParseSimHash(result.Fingerprint)
+		if err != nil {
+			continue
+		}
+		fingerprints[i] = fingerprint
+		valid[i] = true
+	}
+
+	// 每个页面所属组的代表页面下标
+	owner := make([]int, len(results))
+	var representatives []int
+	for i := range results {
+		owner[i] = i
+		if !valid[i] {
+			continue
+		}
+		for _, r := range representatives {
+			if utils.SimHashSimilarity(fingerprints[i], fingerprints[r]) >= threshold {
+				owner[i] = r
+				break
+			}
+		}
+		if owner[i] == i {
+			representatives = append(representatives, i)
+		}
+	}
+
+	// 统计各组大小，只为包含多个页面的组编号
+	groupSize := make(map[int]int)
+	for i := range results {
+		if valid[i] {
+			groupSize[owner[i]]++
+		}
+	}
+
+	groupIDs := make(map[int]int)
+	grouped := make([]models.PageResult, 0, len(results))
+	for i, result := range results {
+		if !valid[i] || groupSize[owner[i]] < 2 {
+			grouped = append(grouped, result)
+			continue
+		}
+
+		root := owner[i]
+		if _, ok := groupIDs[root]; !ok {
+			groupIDs[root] = len(groupIDs) + 1
+		}
+		result.NearDuplicateGroup = groupIDs[root]
+		if root != i {
+			result.NearDuplicateOf = results[root].URL
+			if dropDuplicates {
+				continue
+			}
+		}
+		grouped = append(grouped, result)
+	}
+
+	return grouped
+}
+
+// contentFingerprint 计算正文的 SimHash 指纹；文本中没有词时返回空字符串，
+// 避免空白或只有脚本的页面得到相同的全零指纹而被归为一组
+func contentFingerprint(text string) string {
+	if utils.CountWords(text) == 0 {
+		return ""
+	}
+	return utils.FormatSimHash(utils.SimHash(text))
+}
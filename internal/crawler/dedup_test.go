@@ -0,0 +1,80 @@
+package crawler
+
+import (
+	"testing"
+
+	"flaremind/internal/models"
+	"flaremind/pkg/utils"
+)
+
+func TestGroupNearDuplicates(t *testing.T) {
+	article := "Release notes for version two introduce a faster scheduler, improved garbage collection and better tooling for modules"
+	mirror := "Release notes for version two introduce a faster scheduler, improved garbage collection and better tooling for workspaces"
+	other := "Our company was founded in a small garage and now serves customers across the whole world with friendly support"
+
+	results := []models.PageResult{
+		{URL: "https://example.com/release", Fingerprint: utils.FormatSimHash(utils.SimHash(article))},
+		{URL: "https://example.com/about", Fingerprint: utils.FormatSimHash(utils.SimHash(other))},
+		{URL: "https://example.com/print/release", Fingerprint: utils.FormatSimHash(utils.SimHash(mirror))},
+	}
+
+	grouped := GroupNearDuplicates(results, 0.8, false)
+	if len(grouped) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(grouped))
+	}
+	if grouped[0].NearDuplicateGroup != 1 || grouped[2].NearDuplicateGroup != 1 {
+		t.Errorf("Expected release pages to share group 1, got %d and %d", grouped[0].NearDuplicateGroup, grouped[2].NearDuplicateGroup)
+	}
+	if grouped[2].NearDuplicateOf != "https://example.com/release" {
+		t.Errorf("Expected mirror to be near duplicate of release page, got %q", grouped[2].NearDuplicateOf)
+	}
+	if grouped[1].NearDuplicateGroup != 0 || grouped[0].NearDuplicateOf != "" {
+		t.Error("Expected unrelated page and group representative to be unmarked")
+	}
+
+	dropped := GroupNearDuplicates(results, 0.8, true)
+	if len(dropped) != 2 {
+		t.Errorf("Expected near duplicate to be dropped, got %d results", len(dropped))
+	}
+
+	if disabled := GroupNearDuplicates(results, 0, true); len(disabled) != 3 {
+		t.Error("Expected zero threshold to disable grouping")
+	}
+}
+
+func TestGroupNearDuplicates_ComparesWithRepresentative(t *testing.T) {
+	// a 与 b、b 与 c 的相似度达到阈值，a 与 c 不达到
+	results := []models.PageResult{
+		{URL: "https://example.com/a", Fingerprint: utils.FormatSimHash(0x0000000000000000)},
+		{URL: "https://example.com/b", Fingerprint: utils.FormatSimHash(0x00000000000000ff)},
+		{URL: "https://example.com/c", Fingerprint: utils.FormatSimHash(0x000000000000ffff)},
+	}
+
+	grouped := GroupNearDuplicates(results, 0.85, false)
+	if grouped[1].NearDuplicateOf != "https://example.com/a" {
+		t.Errorf("Expected b to be a near duplicate of a, got %q", grouped[1].NearDuplicateOf)
+	}
+	if grouped[2].NearDuplicateGroup != 0 || grouped[2].NearDuplicateOf != "" {
+		t.Errorf("Expected c not to be grouped with a through b, got group %d of %q", grouped[2].NearDuplicateGroup, grouped[2].NearDuplicateOf)
+	}
+}
+
+func TestContentFingerprint(t *testing.T) {
+	for _, text := range []string{"", "   \n\t", "—— … !!"} {
+		if fingerprint := contentFingerprint(text); fingerprint != "" {
+			t.Errorf("contentFingerprint(%q) = %q, want empty", text, fingerprint)
+		}
+	}
+	if fingerprint := contentFingerprint("Release notes for version two"); fingerprint == "" {
+		t.Error("Expected a fingerprint for text with words")
+	}
+
+	// 没有正文的页面不参与分组
+	results := []models.PageResult{
+		{URL: "https://example.com/app", Fingerprint: contentFingerprint("")},
+		{URL: "https://example.com/dashboard", Fingerprint: contentFingerprint(" ")},
+	}
+	if grouped := GroupNearDuplicates(results, 0.9, true); len(grouped) != 2 || grouped[1].NearDuplicateGroup != 0 {
+		t.Errorf("Expected pages without text to stay ungrouped, got %+v", grouped)
+	}
+}
@@ -16,30 +16,24 @@ func NewExtractor() *Extractor {
 	return &Extractor{}
 }
 
-// ExtractMainContent 提取主要内容（使用 Readability 类似算法）
+// ExtractMainContent 提取主要内容（使用 Readability 候选评分算法）
+// 所有 Readability 尝试都找不到候选时，回退到按内容区域整体评分的启发式算法
 func (e *Extractor) ExtractMainContent(html string) (string, error) {
+	return e.extractMainContent(html, nil)
+}
+
+// extractMainContent 提取主要内容，debug 不为 nil 时记录候选块、评分和被移除的元素
+func (e *Extractor) extractMainContent(html string, debug *ExtractionDebug) (string, error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
 	if err != nil {
 		return "", err
 	}
 
-	// 移除不需要的元素
-	doc.Find("script, style, nav, header, footer, aside, .ad, .advertisement, .sidebar, .navigation, .menu, .social, .share, .comments, .comment, iframe, embed, object").Remove()
-
-	// 尝试找到主要内容区域
-	var content *goquery.Selection
-
-	// 优先级：article > main > .content > .post > body
-	if selection := doc.Find("article"); selection.Length() > 0 {
-		content = selection.First()
-	} else if selection := doc.Find("main"); selection.Length() > 0 {
-		content = selection.First()
-	} else if selection := doc.Find(".content, .post, .article, .entry, .post-content"); selection.Length() > 0 {
-		// 使用 Readability 算法选择最佳内容区域
-		content = e.selectBestContent(selection)
-	} else {
-		// 使用 Readability 算法从 body 中选择最佳内容
-		content = e.selectBestContent(doc.Find("body"))
+	content, ok := e.extractReadability(html, debug)
+	if !ok {
+		attempt := debug.newAttempt("heuristic", nil)
+		content = e.extractHeuristic(doc, attempt)
+		debug.selectAttempt(attempt)
 	}
 
 	// 清理内容
@@ -54,13 +48,43 @@ func (e *Extractor) ExtractMainContent(html string) (string, error) {
 	return result, nil
 }
 
-// selectBestContent 使用 Readability 算法选择最佳内容区域
-func (e *Extractor) selectBestContent(selections *goquery.Selection) *goquery.Selection {
+// extractHeuristic 按 article > main > 常见内容 class > body 的优先级选择内容区域
+func (e *Extractor) extractHeuristic(doc *goquery.Document, attempt *AttemptDebug) *goquery.Selection {
+	// 移除不需要的元素
+	noise := noiseElements(doc.Selection, noiseSelector)
+	attempt.recordRemoved(noise, "noise")
+	noise.Remove()
+
+	// 优先级：article > main > .content > .post > body
+	var content *goquery.Selection
+	if selection := doc.Find("article"); selection.Length() > 0 {
+		content = selection.First()
+	} else if selection := doc.Find("main"); selection.Length() > 0 {
+		content = selection.First()
+	} else if selection := doc.Find(".content, .post, .article, .entry, .post-content"); selection.Length() > 0 {
+		content = e.selectBestContent(selection, attempt)
+	} else {
+		content = e.selectBestContent(doc.Find("body"), attempt)
+	}
+	attempt.recordChosen(content.Get(0), nil)
+	if attempt != nil {
+		attempt.TextLength = textLength(content.Text())
+	}
+	return content
+}
+
+// selectBestContent 按整体内容分数选择最佳内容区域
+func (e *Extractor) selectBestContent(selections *goquery.Selection, attempt *AttemptDebug) *goquery.Selection {
 	var bestScore float64
 	var bestSelection *goquery.Selection
 
 	selections.Each(func(i int, s *goquery.Selection) {
 		score := e.calculateContentScore(s)
+		attempt.recordCandidate(s.Get(0), score, ScoreFactors{
+			TextLength:  textLength(s.Text()),
+			LinkDensity: linkDensity(s),
+			Paragraphs:  s.Find("p").Length(),
+		})
 		if score > bestScore {
 			bestScore = score
 			bestSelection = s
@@ -74,24 +98,19 @@ func (e *Extractor) selectBestContent(selections *goquery.Selection) *goquery.Se
 	return bestSelection
 }
 
-// calculateContentScore 计算内容分数（Readability 算法）
+// calculateContentScore 计算内容区域的整体分数
 func (e *Extractor) calculateContentScore(s *goquery.Selection) float64 {
 	// 获取文本内容
 	text := s.Text()
-	textLength := len(strings.TrimSpace(text))
+	length := textLength(text)
 
-	// 如果文本太短，分数很低
-	if textLength < 100 {
+	// 如果文本太短，分数很低（中日韩文本使用较小的阈值）
+	if float64(length) < scaledThreshold(100, text) {
 		return 0
 	}
 
 	// 计算链接密度
-	linkText := ""
-	s.Find("a").Each(func(i int, a *goquery.Selection) {
-		linkText += a.Text() + " "
-	})
-	linkDensity := float64(len(strings.TrimSpace(linkText))) / float64(textLength)
-	if linkDensity > 0.5 {
+	if linkDensity(s) > 0.5 {
 		return 0 // 链接密度太高，可能是导航或广告
 	}
 
@@ -105,12 +124,12 @@ func (e *Extractor) calculateContentScore(s *goquery.Selection) float64 {
 	imageCount := s.Find("img").Length()
 
 	// 基础分数：文本长度
-	score := float64(textLength)
+	score := float64(length)
 
 	// 加分项
-	score += float64(paragraphCount) * 10  // 段落加分
-	score += float64(listCount) * 5         // 列表加分
-	score += float64(imageCount) * 2        // 图片加分
+	score += float64(paragraphCount) * 10 // 段落加分
+	score += float64(listCount) * 5       // 列表加分
+	score += float64(imageCount) * 2      // 图片加分
 
 	// 检查是否包含常见的内容标识符
 	class := s.AttrOr("class", "")
@@ -135,16 +154,23 @@ func (e *Extractor) calculateContentScore(s *goquery.Selection) float64 {
 
 // cleanContent 清理内容
 func (e *Extractor) cleanContent(s *goquery.Selection) {
-	// 移除空的段落和 div
+	// 移除站点配置的保留标记
+	s.RemoveAttr(keepAttr)
+	s.Find("[" + keepAttr + "]").RemoveAttr(keepAttr)
+
+	// 移除空的段落和 div（包含图片、分隔线等的除外）
 	s.Find("p, div").Each(func(i int, elem *goquery.Selection) {
 		text := strings.TrimSpace(elem.Text())
-		if text == "" {
+		if text == "" && elem.Find(keptEmptyElements).Length() == 0 {
 			elem.Remove()
 		}
 	})
 
 	// 移除只有空白字符的元素
 	s.Find("*").Each(func(i int, elem *goquery.Selection) {
+		if elem.Is(keptEmptyElements) {
+			return
+		}
 		text := strings.TrimSpace(elem.Text())
 		html, _ := elem.Html()
 		// 如果元素只包含空白或换行符
@@ -163,16 +189,48 @@ func (e *Extractor) cleanContent(s *goquery.Selection) {
 		if goquery.NodeName(elem) == "a" {
 			return
 		}
-		// 移除其他属性
-		elem.RemoveAttr("class")
+		// 移除其他属性（转换器需要的类名和单元格对齐方式除外）
+		if class := keptClasses(elem); class != "" {
+			elem.SetAttr("class", class)
+		} else {
+			elem.RemoveAttr("class")
+		}
+		if align := textAlignPattern.FindString(elem.AttrOr("style", "")); align != "" && elem.Is("td, th") {
+			elem.SetAttr("style", align)
+		} else {
+			elem.RemoveAttr("style")
+		}
 		elem.RemoveAttr("id")
-		elem.RemoveAttr("style")
 		elem.RemoveAttr("onclick")
 	})
 }
 
+// keptEmptyElements 没有文本但不能当作空元素移除的元素：图片、换行、分隔线和表格单元格（移除单元格会使后面的列错位）
+const keptEmptyElements = "img, picture, source, br, hr, td, th"
+
+// keptClasses 返回清理属性时要保留的类名：公式容器的全部类名（转换器据此识别公式和独立公式），
+// 以及代码块的 language-* 和 lang-* 类名
+func keptClasses(elem *goquery.Selection) string {
+	node := elem.Get(0)
+	if isMathContainer(node) || hasClassPrefix(node, "MathJax") {
+		return elem.AttrOr("class", "")
+	}
+	var kept []string
+	for _, class := range strings.Fields(elem.AttrOr("class", "")) {
+		if strings.HasPrefix(class, "language-") || strings.HasPrefix(class, "lang-") {
+			kept = append(kept, class)
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
 // ExtractText 提取纯文本内容
 func (e *Extractor) ExtractText(html string) (string, error) {
+	return extractPlainText(html)
+}
+
+// extractPlainText 提取 HTML 的纯文本（移除脚本和样式）
+func extractPlainText(html string) (string, error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
 	if err != nil {
 		return "", err
@@ -189,4 +247,3 @@ func (e *Extractor) ExtractText(html string) (string, error) {
 
 	return text, nil
 }
-
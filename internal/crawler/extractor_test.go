@@ -1,6 +1,8 @@
 package crawler
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -72,4 +74,250 @@ func TestExtractor_ExtractText(t *testing.T) {
 	}
 }
 
+func TestExtractor_ReadabilityCorpus(t *testing.T) {
+	extractor := NewExtractor()
+
+	// testdata/readability 中每个 HTML 页面对应一个期望文件：
+	// "+ " 开头的行必须出现在正文中，"- " 开头的行必须被移除
+	pages, err := filepath.Glob(filepath.Join("testdata", "readability", "*.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) == 0 {
+		t.Fatal("no readability corpus pages found")
+	}
+
+	for _, page := range pages {
+		name := strings.TrimSuffix(filepath.Base(page), ".html")
+		t.Run(name, func(t *testing.T) {
+			source, err := os.ReadFile(page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected, err := os.ReadFile(strings.TrimSuffix(page, ".html") + ".txt")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			content, err := extractor.ExtractMainContent(string(source))
+			if err != nil {
+				t.Fatalf("ExtractMainContent() error = %v", err)
+			}
+			text, err := extractor.ExtractText(content)
+			if err != nil {
+				t.Fatalf("ExtractText() error = %v", err)
+			}
+			text = strings.Join(strings.Fields(text), " ")
+
+			for _, line := range strings.Split(string(expected), "\n") {
+				line = strings.TrimSpace(line)
+				switch {
+				case strings.HasPrefix(line, "+ "):
+					if want := strings.TrimPrefix(line, "+ "); !strings.Contains(text, want) {
+						t.Errorf("missing %q in extracted text:\n%s", want, text)
+					}
+				case strings.HasPrefix(line, "- "):
+					if unwanted := strings.TrimPrefix(line, "- "); strings.Contains(text, unwanted) {
+						t.Errorf("unexpected %q in extracted text:\n%s", unwanted, text)
+					}
+				}
+			}
+		})
+	}
+}
 
+func TestExtractor_ReadabilityRelaxedFallback(t *testing.T) {
+	extractor := NewExtractor()
+
+	// 正文位于 class 含 comment 的容器中，第一次尝试会将其移除，放宽清理后应能找回
+	html := `
+		<html>
+			<body>
+				<div class="comment-thread">
+					<p>The first reply explains that the cache is invalidated whenever the configuration file changes on disk.</p>
+					<p>The second reply adds that the invalidation only happens after the debounce interval has elapsed.</p>
+				</div>
+			</body>
+		</html>
+	`
+
+	result, err := extractor.ExtractMainContent(html)
+	if err != nil {
+		t.Fatalf("ExtractMainContent() error = %v", err)
+	}
+	if !strings.Contains(result, "cache is invalidated") || !strings.Contains(result, "debounce interval") {
+		t.Errorf("expected relaxed attempt to recover the thread, got %q", result)
+	}
+}
+
+func TestExtractor_ReadabilityPropagatesScores(t *testing.T) {
+	extractor := NewExtractor()
+
+	// 段落分数传播到共同的父节点，父节点胜过链接密集的列表
+	html := `
+		<html>
+			<body>
+				<div id="links">
+					<p><a href="/a">A long list of links that should never be chosen as content</a></p>
+					<p><a href="/b">Another long link that only points somewhere else entirely</a></p>
+				</div>
+				<div id="text">
+					<p>Paragraph one talks about scoring, propagation, and the weight of commas in text.</p>
+					<p>Paragraph two continues, adding more detail, more commas, and more sentences.</p>
+				</div>
+			</body>
+		</html>
+	`
+
+	result, err := extractor.ExtractMainContent(html)
+	if err != nil {
+		t.Fatalf("ExtractMainContent() error = %v", err)
+	}
+	if !strings.Contains(result, "Paragraph one") || !strings.Contains(result, "Paragraph two") {
+		t.Errorf("expected both paragraphs, got %q", result)
+	}
+	if strings.Contains(result, "A long list of links") {
+		t.Errorf("link-heavy block should not be selected, got %q", result)
+	}
+}
+
+func TestExtractor_CJKMeasurement(t *testing.T) {
+	if length := textLength("  新能源汽车  "); length != 5 {
+		t.Errorf("textLength() = %d, want 5 characters", length)
+	}
+	if threshold := scaledThreshold(25, "English paragraph"); threshold != 25 {
+		t.Errorf("scaledThreshold(English) = %v, want 25", threshold)
+	}
+	if threshold := scaledThreshold(25, "中文段落"); threshold != 10 {
+		t.Errorf("scaledThreshold(CJK) = %v, want 10", threshold)
+	}
+}
+
+func TestExtractor_CJKShortParagraphs(t *testing.T) {
+	extractor := NewExtractor()
+
+	// 中文短段落按字符数不足 25，但应按语言调整后的阈值参与评分
+	html := `
+		<html>
+			<body>
+				<div class="links"><a href="/a">首页</a><a href="/b">新闻</a></div>
+				<div id="main-text">
+					<p>今年全国粮食产量再创新高，达到历史最好水平。</p>
+					<p>专家表示，良种推广、农机应用功不可没。</p>
+					<p>下一步，将继续加强耕地保护和水利建设。</p>
+				</div>
+			</body>
+		</html>
+	`
+
+	article, ok := extractor.extractReadability(html, nil)
+	if !ok {
+		t.Fatal("expected readability to find CJK paragraphs")
+	}
+	text := article.Text()
+	if !strings.Contains(text, "粮食产量再创新高") || !strings.Contains(text, "耕地保护") {
+		t.Errorf("expected CJK paragraphs in result, got %q", text)
+	}
+	if strings.Contains(text, "首页") {
+		t.Errorf("navigation links should be excluded, got %q", text)
+	}
+}
+
+func TestExtractor_KeepsMathScripts(t *testing.T) {
+	extractor := NewExtractor()
+	html := `<html><body><article>
+		<p>The sum of the first n integers, written with a formula, is derived below, step by step, with care.</p>
+		<p>We use induction, a standard technique, to prove <script type="math/tex">\sum_{i=1}^n i = n(n+1)/2</script> for all n.</p>
+		<script>var tracking = true;</script>
+	</article></body></html>`
+
+	content, err := extractor.ExtractMainContent(html)
+	if err != nil {
+		t.Fatalf("ExtractMainContent() error = %v", err)
+	}
+	if !strings.Contains(content, `type="math/tex"`) {
+		t.Errorf("expected the MathJax script to be kept: %s", content)
+	}
+	if strings.Contains(content, "tracking") {
+		t.Errorf("expected other scripts to be removed: %s", content)
+	}
+
+	markdown, err := NewConverter().HTMLToMarkdown(content)
+	if err != nil || !strings.Contains(markdown, `$\sum_{i=1}^n i = n(n+1)/2$`) {
+		t.Errorf("HTMLToMarkdown() = %q, %v, want the TeX source", markdown, err)
+	}
+}
+
+func TestExtractor_ConverterPipeline(t *testing.T) {
+	const intro = `<p>This article explains the topic in detail, with examples, formulas, figures and code, so that readers can follow every step of the derivation.</p>`
+	tests := []struct {
+		name     string
+		body     string
+		contains []string
+		excludes []string
+	}{
+		{
+			name: "katex",
+			body: `<p>Inline <span class="katex"><span class="katex-mathml"><math><semantics><mrow><msup><mi>x</mi><mn>2</mn></msup></mrow><annotation encoding="application/x-tex">x^2</annotation></semantics></math></span><span class="katex-html" aria-hidden="true"><span class="base"><span class="strut" style="height:0.81em;"></span><span class="mord mathnormal">x</span><span class="msupsub">2</span></span></span></span> here.</p>
+				<p><span class="katex-display"><span class="katex"><span class="katex-mathml"><math display="block"><semantics><mrow><mi>y</mi><mo>=</mo><mn>1</mn></mrow><annotation encoding="application/x-tex">y=1</annotation></semantics></math></span><span class="katex-html" aria-hidden="true"><span class="mord mathnormal">y</span><span class="mrel">=</span><span class="mord">1</span></span></span></span></p>`,
+			contains: []string{"Inline $x^2$ here.", "$$\ny=1\n$$"},
+			excludes: []string{"$x^2$x", "$$\ny=1\n$$y"},
+		},
+		{
+			name:     "mathjax 2",
+			body:     `<p>Inline <span class="MathJax_Preview">x2</span><span class="MathJax" id="MathJax-Element-1-Frame"><nobr><span class="math">x2</span></nobr></span><script type="math/tex" id="MathJax-Element-1">x^2</script> here.</p>`,
+			contains: []string{"Inline $x^2$ here."},
+			excludes: []string{"x2"},
+		},
+		{
+			name:     "figure",
+			body:     `<figure><img src="/images/chart.png" alt="Chart"><figcaption>Monthly totals</figcaption></figure>`,
+			contains: []string{"![Chart](https://example.com/images/chart.png)", "*Monthly totals*"},
+		},
+		{
+			name: "lazy images",
+			body: `<p><img src="data:image/gif;base64,R0lGODlh" data-src="lazy/photo.jpg" alt="Photo"></p>
+				<div><img src="/spacer.gif" srcset="small.png 480w, large.png 1200w" alt="Responsive"></div>`,
+			contains: []string{"![Photo](https://example.com/docs/lazy/photo.jpg)", "![Responsive](https://example.com/docs/large.png)"},
+		},
+		{
+			name:     "line breaks and rules",
+			body:     `<p>Line one<br>Line two</p><hr><p>After the rule, the text continues with another sentence.</p>`,
+			contains: []string{"Line one  \nLine two", "\n\n---\n\n"},
+		},
+		{
+			name:     "code language",
+			body:     `<pre><code class="language-go hljs">fmt.Println("hi")</code></pre>`,
+			contains: []string{"```go\nfmt.Println(\"hi\")\n```"},
+		},
+		{
+			name:     "table cells",
+			body:     `<table><tr><th>Name</th><th style="text-align: right; color: red">Count</th></tr><tr><td></td><td>3</td></tr></table>`,
+			contains: []string{"| Name | Count |\n| --- | ---: |\n|  | 3 |"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := `<html><body><article>` + intro + tt.body + `</article></body></html>`
+			content, err := NewExtractor().ExtractMainContent(page)
+			if err != nil {
+				t.Fatalf("ExtractMainContent() error = %v", err)
+			}
+			markdown, err := NewConverter().PageToMarkdown(content, "https://example.com/docs/page")
+			if err != nil {
+				t.Fatalf("PageToMarkdown() error = %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(markdown, want) {
+					t.Errorf("expected %q in:\n%s", want, markdown)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(markdown, unwanted) {
+					t.Errorf("unexpected %q in:\n%s", unwanted, markdown)
+				}
+			}
+		})
+	}
+}
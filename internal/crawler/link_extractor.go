@@ -1,89 +1,349 @@
 package crawler
 
 import (
+	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 
+	"flaremind/internal/models"
 	"flaremind/pkg/utils"
 
 	"github.com/PuerkitoBio/goquery"
 )
 
+var (
+	// onclick 中的跳转：location.href = '...'、window.location = "..."、location.assign('...')
+	onclickLocationPattern = regexp.MustCompile(`location(?:\.href)?\s*=\s*['"]([^'"]+)['"]|location\.(?:assign|replace)\(\s*['"]([^'"]+)['"]\s*\)`)
+	// meta refresh 的 content：例如 "5; url=/next"
+	metaRefreshPattern = regexp.MustCompile(`(?i)^\s*\d*\.?\d*\s*[;,]\s*url\s*=\s*['"]?([^'"]+)['"]?\s*$`)
+)
+
+// Link 从页面中发现的链接
+type Link struct {
+	URL  string
+	Kind models.LinkKind
+	Text string // 锚文本（非 a/area 元素为空）
+	Rel  string // rel 属性原始值
+}
+
 // LinkExtractor 链接提取器
 type LinkExtractor struct {
-	baseURL string
+	pageURL         string // 页面地址，未指定允许的域名时只接受与其同域名的链接
+	baseURL         string // 解析相对地址的基准地址（默认为页面地址）
+	discoverOnclick bool
 }
 
 // NewLinkExtractor 创建新的链接提取器
-func NewLinkExtractor(baseURL string) *LinkExtractor {
+func NewLinkExtractor(pageURL string) *LinkExtractor {
 	return &LinkExtractor{
-		baseURL: baseURL,
+		pageURL: pageURL,
+		baseURL: pageURL,
 	}
 }
 
+// SetBaseURL 设置解析相对地址的基准地址（如 <base href>），同域名检查仍使用页面地址
+func (le *LinkExtractor) SetBaseURL(baseURL string) {
+	le.baseURL = baseURL
+}
+
+// SetDiscoverOnclick 设置是否从 onclick="location.href=..." 中发现链接
+func (le *LinkExtractor) SetDiscoverOnclick(enabled bool) {
+	le.discoverOnclick = enabled
+}
+
 // ExtractLinks 从 HTML 中提取所有链接
 func (le *LinkExtractor) ExtractLinks(html string, allowedDomains []string) ([]string, error) {
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	details, err := le.ExtractLinkDetails(html, allowedDomains)
 	if err != nil {
 		return nil, err
 	}
 
 	var links []string
 	seen := make(map[string]bool)
+	for _, link := range details {
+		if !seen[link.URL] {
+			seen[link.URL] = true
+			links = append(links, link.URL)
+		}
+	}
 
-	doc.Find("a[href]").Each(func(i int, s *goquery.Selection) {
-		href := s.AttrOr("href", "")
-		if href == "" {
-			return
+	return links, nil
+}
+
+// ExtractLinkDetails 从 HTML 中提取所有链接，并标注每个链接的来源类型
+// 同一 URL 以不同类型出现时会分别返回
+func (le *LinkExtractor) ExtractLinkDetails(html string, allowedDomains []string) ([]Link, error) {
+	return le.extractLinkDetails(html, func(normalized string) bool {
+		return le.isAllowed(normalized, allowedDomains)
+	})
+}
+
+// ExtractExternalLinks 从 HTML 中提取不在允许域名内的链接（用于外链检查）
+func (le *LinkExtractor) ExtractExternalLinks(html string, allowedDomains []string) ([]Link, error) {
+	return le.extractLinkDetails(html, func(normalized string) bool {
+		return !le.isAllowed(normalized, allowedDomains)
+	})
+}
+
+// ExtractNavigationLinks 按文档顺序提取导航目录（如文档站点侧边栏）中的站内链接
+// 使用第一个包含链接的选择器，URL 已去重
+func (le *LinkExtractor) ExtractNavigationLinks(html string, selectors []string, allowedDomains []string) ([]string, error) {
+	if len(selectors) == 0 {
+		return nil, nil
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, selector := range selectors {
+		var links []string
+		seen := make(map[string]bool)
+		doc.Find(selector).Find("a[href]").Each(func(i int, s *goquery.Selection) {
+			normalized, ok := le.resolve(s.AttrOr("href", ""))
+			if !ok || seen[normalized] || !le.isAllowed(normalized, allowedDomains) {
+				return
+			}
+			seen[normalized] = true
+			links = append(links, normalized)
+		})
+		if len(links) > 0 {
+			return links, nil
+		}
+	}
+	return nil, nil
+}
+
+// orderByNavigation 将导航目录中的链接按目录顺序排在前面，其余链接保持原有顺序
+func orderByNavigation(links []Link, navigation []string) []Link {
+	if len(navigation) == 0 {
+		return links
+	}
+
+	position := make(map[string]int, len(navigation))
+	for i, link := range navigation {
+		position[link] = i
+	}
+
+	ordered := make([]Link, len(links))
+	copy(ordered, links)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		pi, iok := position[ordered[i].URL]
+		pj, jok := position[ordered[j].URL]
+		if iok != jok {
+			return iok
 		}
+		return iok && pi < pj
+	})
+	return ordered
+}
 
-		// 解析为绝对 URL
-		absoluteURL, err := utils.ResolveURL(le.baseURL, href)
-		if err != nil {
+// extractLinkDetails 提取所有通过 accept 过滤的链接
+func (le *LinkExtractor) extractLinkDetails(html string, accept func(normalized string) bool) ([]Link, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil, err
+	}
+
+	var links []Link
+	seen := make(map[Link]int) // URL 和类型 -> 在 links 中的下标
+
+	add := func(href string, kind models.LinkKind, text, rel string) {
+		normalized, ok := le.resolve(href)
+		if !ok || !accept(normalized) {
 			return
 		}
 
-		// 规范化 URL
-		normalized, err := utils.NormalizeURL(absoluteURL)
-		if err != nil {
+		// 去重；同一链接只要有一处可跟随（没有 nofollow 等标记），就使用该处的 rel
+		link := Link{URL: normalized, Kind: kind, Text: text, Rel: rel}
+		key := Link{URL: normalized, Kind: kind}
+		if i, ok := seen[key]; ok {
+			if links[i].IsNoFollow() && !link.IsNoFollow() {
+				links[i].Rel = rel
+			}
 			return
 		}
+		seen[key] = len(links)
+		links = append(links, link)
+	}
 
-		// 检查是否有效
-		if !utils.IsValidURL(normalized) {
+	// 锚点（rel=next/prev 的锚点视为分页链接）
+	doc.Find("a[href]").Each(func(i int, s *goquery.Selection) {
+		rel := s.AttrOr("rel", "")
+		kind := models.LinkKindAnchor
+		if hasRelToken(rel, "next") {
+			kind = models.LinkKindNext
+		} else if hasRelToken(rel, "prev") || hasRelToken(rel, "previous") {
+			kind = models.LinkKindPrev
+		}
+		add(s.AttrOr("href", ""), kind, strings.TrimSpace(s.Text()), rel)
+	})
+
+	// <link rel="next|prev|alternate">
+	doc.Find("link[rel][href]").Each(func(i int, s *goquery.Selection) {
+		rel := s.AttrOr("rel", "")
+		switch {
+		case hasRelToken(rel, "next"):
+			add(s.AttrOr("href", ""), models.LinkKindNext, "", rel)
+		case hasRelToken(rel, "prev") || hasRelToken(rel, "previous"):
+			add(s.AttrOr("href", ""), models.LinkKindPrev, "", rel)
+		case hasRelToken(rel, "alternate") && isHTMLAlternate(s.AttrOr("type", "")):
+			add(s.AttrOr("href", ""), models.LinkKindAlternate, "", rel)
+		}
+	})
+
+	// 图像映射
+	doc.Find("area[href]").Each(func(i int, s *goquery.Selection) {
+		add(s.AttrOr("href", ""), models.LinkKindArea, s.AttrOr("alt", ""), s.AttrOr("rel", ""))
+	})
+
+	// 框架
+	doc.Find("iframe[src]").Each(func(i int, s *goquery.Selection) {
+		add(s.AttrOr("src", ""), models.LinkKindIframe, "", "")
+	})
+	doc.Find("frame[src]").Each(func(i int, s *goquery.Selection) {
+		add(s.AttrOr("src", ""), models.LinkKindFrame, "", "")
+	})
+
+	// <meta http-equiv="refresh" content="0; url=...">
+	doc.Find("meta[http-equiv][content]").Each(func(i int, s *goquery.Selection) {
+		if !strings.EqualFold(s.AttrOr("http-equiv", ""), "refresh") {
 			return
 		}
+		if match := metaRefreshPattern.FindStringSubmatch(s.AttrOr("content", "")); match != nil {
+			add(match[1], models.LinkKindRefresh, "", "")
+		}
+	})
 
-		// 检查域名限制
-		if len(allowedDomains) > 0 {
-			allowed := false
-			for _, domain := range allowedDomains {
-				// 处理带协议和不带协议的域名
-				domainURL := domain
-				if !strings.HasPrefix(domain, "http://") && !strings.HasPrefix(domain, "https://") {
-					domainURL = "https://" + domain
-				}
-				if utils.IsSameDomain(normalized, domainURL) {
-					allowed = true
-					break
+	// data-href 属性
+	doc.Find("[data-href]").Each(func(i int, s *goquery.Selection) {
+		add(s.AttrOr("data-href", ""), models.LinkKindDataHref, strings.TrimSpace(s.Text()), "")
+	})
+
+	// onclick="location.href=..."
+	if le.discoverOnclick {
+		doc.Find("[onclick]").Each(func(i int, s *goquery.Selection) {
+			for _, match := range onclickLocationPattern.FindAllStringSubmatch(s.AttrOr("onclick", ""), -1) {
+				href := match[1]
+				if href == "" {
+					href = match[2]
 				}
+				add(href, models.LinkKindOnclick, strings.TrimSpace(s.Text()), "")
 			}
-			if !allowed {
-				return
+		})
+	}
+
+	return links, nil
+}
+
+// resolve 将 href 解析为规范化的绝对 URL
+func (le *LinkExtractor) resolve(href string) (string, bool) {
+	href = strings.TrimSpace(href)
+	if href == "" {
+		return "", false
+	}
+
+	// 解析为绝对 URL
+	absoluteURL, err := utils.ResolveURL(le.baseURL, href)
+	if err != nil {
+		return "", false
+	}
+
+	// 规范化 URL
+	normalized, err := utils.NormalizeURL(absoluteURL)
+	if err != nil {
+		return "", false
+	}
+
+	// 检查是否有效
+	if !utils.IsValidURL(normalized) {
+		return "", false
+	}
+
+	return normalized, true
+}
+
+// DocumentBaseURL 返回解析页面中相对地址的基准地址：有 <base href> 时为其相对于页面地址的绝对地址，否则为页面地址
+func DocumentBaseURL(html string, pageURL string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return pageURL
+	}
+	href, ok := doc.Find("base[href]").First().Attr("href")
+	if !ok || strings.TrimSpace(href) == "" {
+		return pageURL
+	}
+	base, err := utils.ResolveURL(pageURL, strings.TrimSpace(href))
+	if err != nil {
+		return pageURL
+	}
+	return base
+}
+
+// isAllowed 检查 URL 是否在允许的域名内
+func (le *LinkExtractor) isAllowed(normalized string, allowedDomains []string) bool {
+	if len(allowedDomains) > 0 {
+		for _, domain := range allowedDomains {
+			// 处理带协议和不带协议的域名
+			domainURL := domain
+			if !strings.HasPrefix(domain, "http://") && !strings.HasPrefix(domain, "https://") {
+				domainURL = "https://" + domain
 			}
-		} else {
-			// 如果没有指定允许的域名，只允许同域名的链接
-			if !utils.IsSameDomain(normalized, le.baseURL) {
-				return
+			if utils.IsSameDomain(normalized, domainURL) {
+				return true
 			}
 		}
+		return false
+	}
 
-		// 去重
-		if !seen[normalized] {
-			seen[normalized] = true
-			links = append(links, normalized)
+	// 如果没有指定允许的域名，只允许同域名的链接
+	return utils.IsSameDomain(normalized, le.pageURL)
+}
+
+// isHTMLAlternate 检查 alternate 链接是否指向 HTML 页面（排除 RSS、Atom 等订阅源）
+func isHTMLAlternate(mimeType string) bool {
+	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+	return mimeType == "" || mimeType == "text/html" || mimeType == "application/xhtml+xml"
+}
+
+// ParseLinkKinds 解析逗号分隔的链接类型列表
+func ParseLinkKinds(value string) ([]models.LinkKind, error) {
+	var kinds []models.LinkKind
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
 		}
-	})
+		kind := models.LinkKind(part)
+		if !isKnownLinkKind(kind) {
+			return nil, fmt.Errorf("unknown link kind %q", part)
+		}
+		kinds = append(kinds, kind)
+	}
+	return kinds, nil
+}
 
-	return links, nil
+// isKnownLinkKind 检查链接类型是否已定义
+func isKnownLinkKind(kind models.LinkKind) bool {
+	for _, known := range models.AllLinkKinds {
+		if kind == known {
+			return true
+		}
+	}
+	return false
 }
 
+// shouldFollowLink 检查链接类型是否在跟随列表中（列表为空时跟随所有类型）
+func shouldFollowLink(link Link, kinds []models.LinkKind) bool {
+	if len(kinds) == 0 {
+		return true
+	}
+	for _, kind := range kinds {
+		if link.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
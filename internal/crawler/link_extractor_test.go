@@ -0,0 +1,216 @@
+package crawler
+
+import (
+	"testing"
+
+	"flaremind/internal/models"
+)
+
+func TestLinkExtractor_ExtractLinkDetails(t *testing.T) {
+	html := `
+		<html>
+			<head>
+				<link rel="next" href="/list?page=2">
+				<link rel="alternate" hreflang="en" href="/en/list">
+				<link rel="alternate" type="application/rss+xml" href="/feed.xml">
+				<meta http-equiv="refresh" content="30; url=/list?refresh=1">
+			</head>
+			<body>
+				<a href="/article/1">Article 1</a>
+				<a href="https://other.com/page">External</a>
+				<a rel="prev" href="/list?page=0">Previous</a>
+				<map><area href="/region" alt="Region"></map>
+				<iframe src="/embed"></iframe>
+				<div data-href="/card">Card</div>
+				<button onclick="location.href='/clicked'">Go</button>
+			</body>
+		</html>
+	`
+
+	extractor := NewLinkExtractor("https://example.com/list")
+	links, err := extractor.ExtractLinkDetails(html, nil)
+	if err != nil {
+		t.Fatalf("ExtractLinkDetails() error = %v", err)
+	}
+
+	expected := map[string]models.LinkKind{
+		"https://example.com/article/1":      models.LinkKindAnchor,
+		"https://example.com/list?page=2":    models.LinkKindNext,
+		"https://example.com/list?page=0":    models.LinkKindPrev,
+		"https://example.com/en/list":        models.LinkKindAlternate,
+		"https://example.com/list?refresh=1": models.LinkKindRefresh,
+		"https://example.com/region":         models.LinkKindArea,
+		"https://example.com/embed":          models.LinkKindIframe,
+		"https://example.com/card":           models.LinkKindDataHref,
+	}
+
+	found := make(map[string]models.LinkKind)
+	for _, link := range links {
+		found[link.URL] = link.Kind
+	}
+	for url, kind := range expected {
+		if found[url] != kind {
+			t.Errorf("Expected %s to be discovered as %s, got %q", url, kind, found[url])
+		}
+	}
+	if _, ok := found["https://example.com/feed.xml"]; ok {
+		t.Error("Feed alternate links should not be discovered")
+	}
+	if _, ok := found["https://other.com/page"]; ok {
+		t.Error("External links should be filtered")
+	}
+	if _, ok := found["https://example.com/clicked"]; ok {
+		t.Error("onclick links should only be discovered when enabled")
+	}
+
+	external, err := extractor.ExtractExternalLinks(html, nil)
+	if err != nil || len(external) != 1 || external[0].URL != "https://other.com/page" {
+		t.Errorf("ExtractExternalLinks() = %v, %v", external, err)
+	}
+
+	extractor.SetDiscoverOnclick(true)
+	links, _ = extractor.ExtractLinkDetails(html, nil)
+	discovered := false
+	for _, link := range links {
+		if link.URL == "https://example.com/clicked" && link.Kind == models.LinkKindOnclick {
+			discovered = true
+		}
+	}
+	if !discovered {
+		t.Error("Expected onclick link to be discovered when enabled")
+	}
+}
+
+func TestParseLinkKinds(t *testing.T) {
+	kinds, err := ParseLinkKinds("anchor, next")
+	if err != nil || len(kinds) != 2 || kinds[1] != models.LinkKindNext {
+		t.Errorf("ParseLinkKinds() = %v, %v", kinds, err)
+	}
+
+	if _, err := ParseLinkKinds("anchor,script"); err == nil {
+		t.Error("Expected error for unknown link kind")
+	}
+
+	if !shouldFollowLink(Link{Kind: models.LinkKindIframe}, nil) {
+		t.Error("Expected empty kind list to follow all links")
+	}
+	if shouldFollowLink(Link{Kind: models.LinkKindIframe}, kinds) {
+		t.Error("Expected iframe link not to be followed")
+	}
+}
+
+func TestLinkExtractor_ExtractNavigationLinks(t *testing.T) {
+	extractor := NewLinkExtractor("https://docs.example.com/intro")
+	html := `
+		<html><body>
+			<nav class="menu">
+				<a href="/intro">Intro</a>
+				<a href="/guide/install">Install</a>
+				<a href="/guide/usage">Usage</a>
+				<a href="/guide/install#step-2">Install, step 2</a>
+				<a href="https://github.com/example/docs">GitHub</a>
+			</nav>
+			<main><a href="/blog">Blog</a></main>
+		</body></html>
+	`
+
+	links, err := extractor.ExtractNavigationLinks(html, []string{".missing", "nav.menu"}, []string{"docs.example.com"})
+	if err != nil {
+		t.Fatalf("ExtractNavigationLinks() error = %v", err)
+	}
+	want := []string{
+		"https://docs.example.com/intro",
+		"https://docs.example.com/guide/install",
+		"https://docs.example.com/guide/usage",
+	}
+	if len(links) != len(want) {
+		t.Fatalf("ExtractNavigationLinks() = %v, want %v", links, want)
+	}
+	for i := range want {
+		if links[i] != want[i] {
+			t.Errorf("links[%d] = %s, want %s", i, links[i], want[i])
+		}
+	}
+
+	ordered := orderByNavigation([]Link{
+		{URL: "https://docs.example.com/blog"},
+		{URL: "https://docs.example.com/guide/usage"},
+		{URL: "https://docs.example.com/intro"},
+	}, links)
+	got := []string{ordered[0].URL, ordered[1].URL, ordered[2].URL}
+	wantOrder := []string{
+		"https://docs.example.com/intro",
+		"https://docs.example.com/guide/usage",
+		"https://docs.example.com/blog",
+	}
+	for i := range wantOrder {
+		if got[i] != wantOrder[i] {
+			t.Errorf("orderByNavigation() = %v, want %v", got, wantOrder)
+			break
+		}
+	}
+}
+
+func TestDocumentBaseURL(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"no base", `<html><head></head><body></body></html>`, "https://example.com/docs/page.html"},
+		{"relative base", `<html><head><base href="/v2/"></head></html>`, "https://example.com/v2/"},
+		{"absolute base", `<html><head><base href="https://cdn.example.com/a/"></head></html>`, "https://cdn.example.com/a/"},
+		{"target only", `<html><head><base target="_blank"></head></html>`, "https://example.com/docs/page.html"},
+	}
+	for _, tt := range tests {
+		if got := DocumentBaseURL(tt.html, "https://example.com/docs/page.html"); got != tt.want {
+			t.Errorf("%s: DocumentBaseURL() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLinkExtractor_BaseURLOnOtherDomain(t *testing.T) {
+	html := `<html><head><base href="https://cdn.examplecdn.net/assets/"></head><body>
+		<a href="https://example.com/docs/next">Next</a>
+		<a href="/about">About</a>
+		<a href="logo.png">Logo</a>
+	</body></html>`
+	extractor := NewLinkExtractor("https://example.com/docs/page")
+	extractor.SetBaseURL(DocumentBaseURL(html, "https://example.com/docs/page"))
+
+	links, err := extractor.ExtractLinks(html, nil)
+	if err != nil {
+		t.Fatalf("ExtractLinks() error = %v", err)
+	}
+	// 相对地址按 <base href> 解析到 CDN，同域名检查仍以页面地址为准
+	if len(links) != 1 || links[0] != "https://example.com/docs/next" {
+		t.Errorf("ExtractLinks() = %v, want only the page's own link", links)
+	}
+}
+
+func TestLinkExtractor_FollowableDuplicateWins(t *testing.T) {
+	extractor := NewLinkExtractor("https://example.com/page")
+	html := `<html><body>
+		<a rel="nofollow" href="/a">comment</a><a href="/a">nav</a>
+		<a href="/b">nav</a><a rel="ugc" href="/b">comment</a>
+		<a rel="sponsored" href="/c">ad</a><a rel="nofollow" href="/c">ad</a>
+	</body></html>`
+
+	links, err := extractor.ExtractLinkDetails(html, nil)
+	if err != nil {
+		t.Fatalf("ExtractLinkDetails() error = %v", err)
+	}
+	want := map[string]bool{
+		"https://example.com/a": false,
+		"https://example.com/b": false,
+		"https://example.com/c": true,
+	}
+	if len(links) != len(want) {
+		t.Fatalf("ExtractLinkDetails() = %+v, want %d links", links, len(want))
+	}
+	for _, link := range links {
+		if link.IsNoFollow() != want[link.URL] {
+			t.Errorf("%s: IsNoFollow() = %v, want %v (rel %q)", link.URL, link.IsNoFollow(), want[link.URL], link.Rel)
+		}
+	}
+}
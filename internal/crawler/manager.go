@@ -8,27 +8,43 @@ import (
 	"time"
 
 	"flaremind/internal/cache"
+	"flaremind/internal/graph"
 	"flaremind/internal/models"
+	"flaremind/internal/profile"
 	"flaremind/internal/queue"
+	"flaremind/internal/schema"
 	"flaremind/pkg/utils"
 
 	"golang.org/x/time/rate"
 )
 
+// pageContent 提取的正文 HTML 及解析其中相对地址的基准地址
+type pageContent struct {
+	html    string
+	baseURL string
+}
+
 // CrawlManager 爬取管理器
 type CrawlManager struct {
 	renderer   *Renderer
-	extractor  *Extractor
+	extractor  ContentExtractor
 	converter  *Converter
 	cache      *cache.Cache
 	maxWorkers int
 	timeout    time.Duration
 	rateLimiter *rate.Limiter
 	delay      time.Duration
+
+	schema    *schema.Schema     // 字段提取配置（为 nil 时不提取）
+	profiles  *profile.Set       // 站点提取配置（为 nil 时只使用通用算法）
+	report    models.CrawlReport // 最近一次爬取的报告
+	linkGraph *graph.Graph       // 最近一次爬取的链接图
+
+	extractionDebug func(*ExtractionDebug) // 接收每个页面正文提取的调试信息（为 nil 时不记录）
 }
 
-// NewCrawlManager 创建新的爬取管理器
-func NewCrawlManager(renderer *Renderer, extractor *Extractor, converter *Converter, cache *cache.Cache, maxWorkers int, timeout time.Duration) *CrawlManager {
+// NewCrawlManager 创建新的爬取管理器，extractor 为正文提取策略（*Extractor、NewStrategy 创建的策略或它们的组合）
+func NewCrawlManager(renderer *Renderer, extractor ContentExtractor, converter *Converter, cache *cache.Cache, maxWorkers int, timeout time.Duration) *CrawlManager {
 	return &CrawlManager{
 		renderer:    renderer,
 		extractor:   extractor,
@@ -41,6 +57,22 @@ func NewCrawlManager(renderer *Renderer, extractor *Extractor, converter *Conver
 	}
 }
 
+// SetSchema 设置字段提取配置，提取结果保存在 PageResult.Data 中
+func (cm *CrawlManager) SetSchema(s *schema.Schema) {
+	cm.schema = s
+}
+
+// SetProfiles 设置站点提取配置，匹配的页面使用配置的标题、日期和导航目录选择器
+// 按配置提取正文需要在提取策略中使用 profile 策略
+func (cm *CrawlManager) SetProfiles(profiles *profile.Set) {
+	cm.profiles = profiles
+}
+
+// SetExtractionDebug 设置正文提取调试信息的接收函数，每个页面提取正文后调用一次（可能被多个 worker 并发调用）
+func (cm *CrawlManager) SetExtractionDebug(handler func(*ExtractionDebug)) {
+	cm.extractionDebug = handler
+}
+
 // Crawl 执行整站爬取
 func (cm *CrawlManager) Crawl(ctx context.Context, startURL string, config models.CrawlConfig) ([]models.PageResult, error) {
 	// 规范化起始 URL
@@ -69,15 +101,143 @@ func (cm *CrawlManager) Crawl(ctx context.Context, startURL string, config model
 
 	// 初始化队列和深度映射
 	q := queue.NewQueue()
+	q.SetTrapDetector(queue.NewTrapDetector(config.Traps))
+	budgets := NewBudgetTracker(config.Budgets)
 	depthMap := make(map[string]int)
 	var depthMu sync.RWMutex
-	q.Add(normalizedStartURL)
+	budgets.Reserve(normalizedStartURL, 0, q)
 	depthMap[normalizedStartURL] = 0
 
-	// 结果存储
+	// 从 sitemap 加入种子 URL
+	sitemapURLs := make(map[string]bool)
+	if config.SitemapURL != "" {
+		seeds, err := FetchSitemapURLs(ctx, config.SitemapURL, cm.timeout)
+		if err != nil {
+			log.Printf("Failed to read sitemap %s: %v", config.SitemapURL, err)
+		}
+		startExtractor := NewLinkExtractor(normalizedStartURL)
+		for _, seed := range seeds {
+			normalized, ok := startExtractor.resolve(seed)
+			if !ok || !startExtractor.isAllowed(normalized, config.AllowedDomains) {
+				continue
+			}
+			sitemapURLs[normalized] = true
+			if normalized == normalizedStartURL || config.MaxDepth < 1 {
+				continue
+			}
+			if budgets.Reserve(normalized, 1, q) {
+				depthMap[normalized] = 1
+			}
+		}
+		log.Printf("Loaded %d URLs from sitemap %s", len(sitemapURLs), config.SitemapURL)
+	}
+
+	// 结果存储（pageCount 只统计非重复页面）
 	var results []models.PageResult
+	var pageCount int
 	var resultsMu sync.Mutex
 
+	// 规范 URL -> 首个爬取到该规范 URL 的页面
+	canonicalOwners := make(map[string]string)
+	var canonicalMu sync.Mutex
+
+	// 被 noindex 指令排除在结果之外的页面，以及渲染失败的页面
+	var noIndexURLs []string
+	var failedURLs []models.FailedURL
+	var extractionErrors []models.ExtractionError
+
+	// 页面 -> 提取的正文 HTML（仅在启用模板内容学习时保留，爬取结束后统一清理）
+	contents := make(map[string]pageContent)
+
+	// 链接图
+	linkGraph := graph.New()
+	linkGraph.AddNode(normalizedStartURL)
+	cm.linkGraph = linkGraph
+
+	// 导航目录（文档站点侧边栏）中的页面 -> 首次出现的目录位置
+	navOrder := make(map[string]int)
+	var navMu sync.Mutex
+
+	// enqueueLinks 提取页面链接，记录到链接图，并在 follow 为 true 时添加到队列
+	// 站点配置指定了导航目录时，目录中的链接按目录顺序优先入队
+	enqueueLinks := func(pageURL, finalURL, html string, depth int, follow bool, siteProfile *profile.Profile) {
+		linkExtractor := NewLinkExtractor(finalURL)
+		linkExtractor.SetBaseURL(DocumentBaseURL(html, finalURL))
+		linkExtractor.SetDiscoverOnclick(config.DiscoverOnclickLinks)
+		links, err := linkExtractor.ExtractLinkDetails(html, config.AllowedDomains)
+		if err != nil {
+			log.Printf("Failed to extract links from %s: %v", pageURL, err)
+			return
+		}
+
+		if siteProfile != nil && len(siteProfile.Sidebar) > 0 {
+			navigation, err := linkExtractor.ExtractNavigationLinks(html, siteProfile.Sidebar, config.AllowedDomains)
+			if err != nil {
+				log.Printf("Failed to extract navigation links from %s: %v", pageURL, err)
+			}
+			navMu.Lock()
+			for _, link := range navigation {
+				if _, ok := navOrder[link]; !ok {
+					navOrder[link] = len(navOrder) + 1
+				}
+			}
+			navMu.Unlock()
+			links = orderByNavigation(links, navigation)
+		}
+
+		for _, detail := range links {
+			linkGraph.AddEdge(models.LinkEdge{
+				Source: pageURL,
+				Target: detail.URL,
+				Text:   detail.Text,
+				Kind:   detail.Kind,
+			})
+		}
+
+		// 记录外链（只记录，不跟随）
+		if config.RecordExternalLinks {
+			if external, err := linkExtractor.ExtractExternalLinks(html, config.AllowedDomains); err == nil {
+				for _, detail := range external {
+					linkGraph.AddEdge(models.LinkEdge{
+						Source:   pageURL,
+						Target:   detail.URL,
+						Text:     detail.Text,
+						Kind:     detail.Kind,
+						External: true,
+					})
+				}
+			}
+		}
+
+		if !follow || depth >= config.MaxDepth {
+			return
+		}
+
+		addedCount := 0
+		for _, detail := range links {
+			if !shouldFollowLink(detail, config.FollowLinkKinds) {
+				continue
+			}
+			if config.RespectNofollowLinks && detail.IsNoFollow() {
+				continue
+			}
+			link := detail.URL
+			if !q.IsVisited(link) {
+				// 只记录真正入队的 URL 的深度（已在队列中的 URL 保持首次发现时的深度）；
+				// 持有锁直到写入，避免调度方在写入前取出该 URL
+				depthMu.Lock()
+				if budgets.Reserve(link, depth+1, q) {
+					depthMap[link] = depth + 1
+					addedCount++
+				}
+				depthMu.Unlock()
+			}
+		}
+		if addedCount > 0 {
+			log.Printf("Added %d new links from %s (queue size: %d, visited: %d)", addedCount, pageURL, q.Size(), q.VisitedCount())
+		}
+	}
+
 	// Worker 通道（包含 URL 和深度）
 	type urlDepth struct {
 		url   string
@@ -102,7 +262,7 @@ func (cm *CrawlManager) Crawl(ctx context.Context, startURL string, config model
 
 					// 检查深度和数量限制
 					resultsMu.Lock()
-					currentCount := len(results)
+					currentCount := pageCount
 					resultsMu.Unlock()
 
 					if currentCount >= config.MaxPages {
@@ -127,66 +287,169 @@ func (cm *CrawlManager) Crawl(ctx context.Context, startURL string, config model
 					}
 
 					// 爬取页面
-					html, err := cm.renderer.Render(ud.url)
+					page, err := cm.renderer.RenderPage(ud.url)
 					if err != nil {
 						log.Printf("Failed to render %s: %v", ud.url, err)
+						resultsMu.Lock()
+						failedURLs = append(failedURLs, models.FailedURL{URL: ud.url, Error: err.Error()})
+						resultsMu.Unlock()
 						continue
 					}
+					html := page.HTML
 
-					// 提取主要内容
-					content, err := cm.extractor.ExtractMainContent(html)
+					// 解析规范 URL（优先 <link rel="canonical">，其次 Link 响应头）
+					canonical := ExtractCanonical(html, page.FinalURL)
+					if canonical == "" {
+						canonical = CanonicalFromLinkHeader(page.Header.Values("Link"), page.FinalURL)
+					}
+					if canonical == "" {
+						canonical = ud.url
+					}
+
+					// 规范 URL 加入已访问集合，避免其变体被重新入队
+					q.MarkVisited(canonical)
+
+					canonicalMu.Lock()
+					owner, exists := canonicalOwners[canonical]
+					if !exists {
+						canonicalOwners[canonical] = ud.url
+					}
+					canonicalMu.Unlock()
+
+					// 与已爬取页面共享规范 URL，标记为重复并跳过后续处理
+					if exists && owner != ud.url {
+						resultsMu.Lock()
+						results = append(results, models.PageResult{
+							URL:         ud.url,
+							Depth:       ud.depth,
+							StatusCode:  page.StatusCode,
+							Canonical:   canonical,
+							DuplicateOf: owner,
+						})
+						resultsMu.Unlock()
+						log.Printf("Skipped duplicate %s (canonical: %s, duplicate of: %s)", ud.url, canonical, owner)
+						continue
+					}
+
+					// 匹配站点配置（用于正文提取和导航目录）
+					siteProfile := cm.profiles.Match(ud.url, html)
+
+					// 解析 robots 指令（meta robots 和 X-Robots-Tag）
+					var robots RobotsDirectives
+					if config.RespectMetaRobots {
+						robots = robots.Merge(ExtractMetaRobots(html))
+					}
+					if config.RespectXRobotsTag {
+						robots = robots.Merge(XRobotsTagDirectives(page.Header.Values("X-Robots-Tag")))
+					}
+
+					// noindex 页面仍提取链接，但不保存结果
+					if robots.NoIndex {
+						resultsMu.Lock()
+						noIndexURLs = append(noIndexURLs, ud.url)
+						resultsMu.Unlock()
+						log.Printf("Skipped noindex page %s", ud.url)
+						enqueueLinks(ud.url, page.FinalURL, html, ud.depth, !robots.NoFollow, siteProfile)
+						continue
+					}
+
+					// 按提取策略提取主要内容
+					content, err := cm.extractor.ExtractContent(html, ud.url)
 					if err != nil {
 						log.Printf("Failed to extract content from %s: %v", ud.url, err)
 						continue
 					}
 
-					// 转换为 Markdown
-					markdown, err := cm.converter.HTMLToMarkdown(content)
+					// 记录正文提取的候选块、评分和被移除的元素
+					if explainer, ok := cm.extractor.(ExtractionExplainer); ok && cm.extractionDebug != nil {
+						if debug, err := explainer.ExplainContent(html, ud.url); err == nil {
+							debug.URL = ud.url
+							cm.extractionDebug(debug)
+						} else {
+							log.Printf("Failed to explain extraction for %s: %v", ud.url, err)
+						}
+					}
+
+					// 转换为 Markdown（链接和图片解析为绝对地址）
+					baseURL := DocumentBaseURL(html, page.FinalURL)
+					markdown, err := cm.converter.PageToMarkdown(content, baseURL)
 					if err != nil {
 						log.Printf("Failed to convert to markdown from %s: %v", ud.url, err)
 						continue
 					}
 
+					// 计算正文指纹（用于近似重复检测）和词数
+					fingerprint := ""
+					wordCount := 0
+					if text, err := extractPlainText(content); err == nil {
+						fingerprint = contentFingerprint(text)
+						wordCount = utils.CountWords(text)
+					}
+					info := ExtractPageInfo(html, page.FinalURL)
+					if info.Metadata.Language == "" {
+						info.Metadata.Language = page.Header.Get("Content-Language")
+					}
+					profileName, platform := "", ""
+					if siteProfile != nil {
+						profileName, platform = siteProfile.Name, siteProfile.Platform
+						applyProfileInfo(siteProfile, html, &info)
+					}
+					var metadata *models.PageMetadata
+					if info.Metadata != (models.PageMetadata{}) {
+						metadata = &info.Metadata
+					}
+					finalURL := ""
+					if len(page.RedirectChain) > 0 {
+						finalURL = page.FinalURL
+					}
+
 					// 缓存结果
 					cm.cache.Set(ud.url, markdown, 24*time.Hour)
 
 					// 保存结果
 					result := models.PageResult{
-						URL:      ud.url,
-						Markdown: markdown,
-						Depth:    ud.depth,
+						URL:         ud.url,
+						Markdown:    markdown,
+						Depth:       ud.depth,
+						StatusCode:  page.StatusCode,
+						Canonical:   canonical,
+						Fingerprint: fingerprint,
+
+						Title:          info.Title,
+						Description:    info.Description,
+						H1:             info.H1,
+						WordCount:      wordCount,
+						RedirectChain:  page.RedirectChain,
+						FinalURL:       finalURL,
+						Metadata:       metadata,
+						StructuredData: ExtractStructuredData(html, page.FinalURL),
+						Profile:        profileName,
+						Platform:       platform,
+					}
+
+					// 按字段提取配置提取结构化记录
+					var fieldErrors []*schema.FieldError
+					if cm.schema != nil {
+						result.Data, fieldErrors = cm.schema.Extract(html, ud.url, baseURL)
 					}
 
 					resultsMu.Lock()
-					if len(results) < config.MaxPages {
+					if pageCount < config.MaxPages {
 						results = append(results, result)
-						log.Printf("Successfully crawled %s (depth: %d, total: %d)", ud.url, ud.depth, len(results))
+						if config.BoilerplateThreshold > 0 {
+							contents[ud.url] = pageContent{html: content, baseURL: baseURL}
+						}
+						for _, fieldErr := range fieldErrors {
+							log.Printf("Extraction error: %v", fieldErr)
+							extractionErrors = append(extractionErrors, models.ExtractionError{URL: fieldErr.URL, Field: fieldErr.Field, Error: fieldErr.Message})
+						}
+						pageCount++
+						log.Printf("Successfully crawled %s (depth: %d, total: %d)", ud.url, ud.depth, pageCount)
 					}
 					resultsMu.Unlock()
 
 					// 提取链接并添加到队列
-					if ud.depth < config.MaxDepth {
-						linkExtractor := NewLinkExtractor(ud.url)
-						links, err := linkExtractor.ExtractLinks(html, config.AllowedDomains)
-						if err == nil {
-							addedCount := 0
-							for _, link := range links {
-								if !q.IsVisited(link) {
-									depthMu.Lock()
-									depthMap[link] = ud.depth + 1
-									depthMu.Unlock()
-									if q.Add(link) {
-										addedCount++
-									}
-								}
-							}
-							if addedCount > 0 {
-								log.Printf("Added %d new links from %s (queue size: %d, visited: %d)", addedCount, ud.url, q.Size(), q.VisitedCount())
-							}
-						} else {
-							log.Printf("Failed to extract links from %s: %v", ud.url, err)
-						}
-					}
+					enqueueLinks(ud.url, page.FinalURL, html, ud.depth, !robots.NoFollow, siteProfile)
 				}
 			}
 		}()
@@ -199,14 +462,14 @@ func (cm *CrawlManager) Crawl(ctx context.Context, startURL string, config model
 		maxEmptyCount := 50 // 增加到 50 次（5秒），给 worker 更多时间处理
 		lastQueueSize := 0
 		stableCount := 0
-		
+
 		for {
 			select {
 			case <-ctx.Done():
 				return
 			default:
 				resultsMu.Lock()
-				currentCount := len(results)
+				currentCount := pageCount
 				resultsMu.Unlock()
 
 				if currentCount >= config.MaxPages {
@@ -220,7 +483,7 @@ func (cm *CrawlManager) Crawl(ctx context.Context, startURL string, config model
 					time.Sleep(200 * time.Millisecond) // 增加等待时间到 200ms
 					emptyCount++
 					currentQueueSize := q.Size()
-					
+
 					// 检查队列大小是否稳定（没有新链接被添加）
 					if currentQueueSize == lastQueueSize {
 						stableCount++
@@ -230,7 +493,7 @@ func (cm *CrawlManager) Crawl(ctx context.Context, startURL string, config model
 						log.Printf("Queue size changed: %d -> %d, resetting counters", lastQueueSize, currentQueueSize)
 					}
 					lastQueueSize = currentQueueSize
-					
+
 					// 如果队列一直为空且已经访问过一些 URL，检查是否所有任务都完成
 					if currentQueueSize == 0 && q.VisitedCount() > 0 {
 						// 需要等待足够长时间，确保所有 worker 都完成处理
@@ -288,8 +551,113 @@ func (cm *CrawlManager) Crawl(ctx context.Context, startURL string, config model
 	// 等待所有 workers 完成
 	wg.Wait()
 
-	log.Printf("Crawl completed: %d pages crawled, %d URLs visited", len(results), q.VisitedCount())
+	duplicateCount := len(results) - pageCount
+
+	// 根据链接图计算最短发现路径、来源页面、入链数和 PageRank
+	paths := linkGraph.ShortestPaths(normalizedStartURL)
+	inDegree := linkGraph.InDegree()
+	pageRank := linkGraph.PageRank(graph.DefaultDamping, 100)
+	for i := range results {
+		results[i].InSitemap = sitemapURLs[results[i].URL]
+		results[i].InLinks = inDegree[results[i].URL]
+		results[i].PageRank = pageRank[results[i].URL]
+		results[i].NavOrder = navOrder[results[i].URL]
+		path := paths[results[i].URL]
+		results[i].DiscoveryPath = path
+		if len(path) >= 2 {
+			results[i].Referrer = path[len(path)-2]
+		}
+	}
+
+	// 站点模板内容学习（在近似重复检测之前，使指纹不受模板内容影响）
+	if config.BoilerplateThreshold > 0 {
+		cm.removeBoilerplate(results, contents, config)
+	}
+
+	// 近似重复检测
+	if config.NearDuplicateThreshold > 0 {
+		before := len(results)
+		results = GroupNearDuplicates(results, config.NearDuplicateThreshold, config.DropNearDuplicates)
+		if dropped := before - len(results); dropped > 0 {
+			log.Printf("Dropped %d near-duplicate pages (threshold: %.2f)", dropped, config.NearDuplicateThreshold)
+		}
+	}
+
+	cm.report = models.CrawlReport{
+		TrappedURLs:     q.Trapped(),
+		BudgetExhausted: budgets.Exhausted(),
+		NoIndexURLs:     noIndexURLs,
+		FailedURLs:      failedURLs,
+
+		ExtractionErrors: extractionErrors,
+	}
+	if len(cm.report.TrappedURLs) > 0 {
+		log.Printf("Skipped %d URLs caught by crawler trap rules", len(cm.report.TrappedURLs))
+	}
+
+	log.Printf("Crawl completed: %d pages crawled, %d duplicates, %d URLs visited", pageCount, duplicateCount, q.VisitedCount())
 
 	return results, nil
 }
 
+// removeBoilerplate 统计各主机页面正文中重复出现的块，从每个页面中移除模板内容，
+// 并重新生成 Markdown、指纹和词数
+func (cm *CrawlManager) removeBoilerplate(results []models.PageResult, contents map[string]pageContent, config models.CrawlConfig) {
+	minPages := config.BoilerplateMinPages
+	if minPages <= 0 {
+		minPages = DefaultBoilerplateMinPages
+	}
+
+	detector := NewBoilerplateDetector(config.BoilerplateThreshold, minPages)
+	for _, result := range results {
+		if content, ok := contents[result.URL]; ok {
+			detector.Add(result.URL, content.html)
+		}
+	}
+
+	cleanedPages, removedBlocks := 0, 0
+	for i := range results {
+		content, ok := contents[results[i].URL]
+		if !ok {
+			continue
+		}
+		cleaned, removed, err := detector.Clean(results[i].URL, content.html)
+		if err != nil {
+			log.Printf("Failed to remove boilerplate from %s: %v", results[i].URL, err)
+			continue
+		}
+		if removed == 0 {
+			continue
+		}
+
+		markdown, err := cm.converter.PageToMarkdown(cleaned, content.baseURL)
+		if err != nil {
+			log.Printf("Failed to convert to markdown from %s: %v", results[i].URL, err)
+			continue
+		}
+		results[i].Markdown = markdown
+		results[i].BoilerplateRemoved = removed
+		if text, err := extractPlainText(cleaned); err == nil {
+			results[i].Fingerprint = contentFingerprint(text)
+			results[i].WordCount = utils.CountWords(text)
+		}
+		cm.cache.Set(results[i].URL, markdown, 24*time.Hour)
+
+		cleanedPages++
+		removedBlocks += removed
+	}
+
+	if removedBlocks > 0 {
+		log.Printf("Removed %d boilerplate blocks from %d pages (threshold: %.2f)", removedBlocks, cleanedPages, config.BoilerplateThreshold)
+	}
+}
+
+// Report 返回最近一次爬取的报告
+func (cm *CrawlManager) Report() models.CrawlReport {
+	return cm.report
+}
+
+// LinkGraph 返回最近一次爬取的链接图
+func (cm *CrawlManager) LinkGraph() *graph.Graph {
+	return cm.linkGraph
+}
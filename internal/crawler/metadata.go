@@ -0,0 +1,302 @@
+package crawler
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"flaremind/internal/models"
+	"flaremind/pkg/utils"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// PageInfo 页面的基础 SEO 信息和元数据
+type PageInfo struct {
+	Title       string
+	Description string
+	H1          []string
+	Metadata    models.PageMetadata
+}
+
+// publishedMetaKeys 发布时间的 meta 名称（按优先级）
+var publishedMetaKeys = []string{
+	"article:published_time", "og:published_time", "datePublished", "date", "pubdate",
+	"publishdate", "publish-date", "DC.date.issued", "dcterms.created", "DC.date", "sailthru.date",
+}
+
+// modifiedMetaKeys 修改时间的 meta 名称（按优先级）
+var modifiedMetaKeys = []string{
+	"article:modified_time", "og:updated_time", "dateModified", "last-modified", "dcterms.modified", "DC.date.modified",
+}
+
+// dateLayouts 可识别的日期格式及对应的输出格式（不含时区的日期不补充时区）
+var dateLayouts = []struct {
+	layout string
+	output string
+}{
+	{time.RFC3339Nano, time.RFC3339},
+	{"2006-01-02T15:04:05-0700", time.RFC3339},
+	{"2006-01-02T15:04:05", "2006-01-02T15:04:05"},
+	{"2006-01-02T15:04", "2006-01-02T15:04:05"},
+	{"2006-01-02 15:04:05", "2006-01-02T15:04:05"},
+	{"2006-01-02 15:04", "2006-01-02T15:04:05"},
+	{time.RFC1123Z, time.RFC3339},
+	{time.RFC1123, time.RFC3339},
+	{"2006-01-02", "2006-01-02"},
+	{"2006/01/02", "2006-01-02"},
+	{"2006年1月2日", "2006-01-02"},
+}
+
+// ExtractPageInfo 从渲染后的 HTML 中提取标题、描述、H1 以及作者、语言、日期、OpenGraph、Twitter 卡片和图标
+func ExtractPageInfo(html string, pageURL string) PageInfo {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return PageInfo{}
+	}
+
+	info := PageInfo{
+		Title:       collapseSpace(doc.Find("title").First().Text()),
+		Description: metaContent(doc, "description"),
+	}
+	doc.Find("h1").Each(func(i int, s *goquery.Selection) {
+		info.H1 = append(info.H1, collapseSpace(s.Text()))
+	})
+
+	objects := jsonLDObjects(doc)
+	metadata := &info.Metadata
+
+	metadata.Author = metaContent(doc, "author", "article:author")
+	if metadata.Author == "" {
+		metadata.Author = jsonLDAuthor(objects)
+	}
+	if metadata.Author == "" {
+		metadata.Author = collapseSpace(doc.Find(`[rel~="author"]`).First().Text())
+	}
+
+	metadata.Language = strings.TrimSpace(doc.Find("html").AttrOr("lang", ""))
+	if metadata.Language == "" {
+		doc.Find("meta[http-equiv][content]").EachWithBreak(func(i int, s *goquery.Selection) bool {
+			if strings.EqualFold(strings.TrimSpace(s.AttrOr("http-equiv", "")), "content-language") {
+				metadata.Language = strings.TrimSpace(s.AttrOr("content", ""))
+				return false
+			}
+			return true
+		})
+	}
+
+	metadata.Published = firstNonEmpty(
+		metaContent(doc, publishedMetaKeys...),
+		jsonLDString(objects, "datePublished"),
+		timeDatetime(doc, `time[itemprop="datePublished"], time[pubdate]`),
+		timeDatetime(doc, "article time[datetime]"),
+	)
+	metadata.Modified = firstNonEmpty(
+		metaContent(doc, modifiedMetaKeys...),
+		jsonLDString(objects, "dateModified"),
+		timeDatetime(doc, `time[itemprop="dateModified"]`),
+	)
+	metadata.Published = normalizeDate(metadata.Published)
+	metadata.Modified = normalizeDate(metadata.Modified)
+
+	metadata.Favicon = extractFavicon(doc, pageURL)
+
+	openGraph := models.OpenGraph{
+		Title:       metaContent(doc, "og:title"),
+		Description: metaContent(doc, "og:description"),
+		Type:        metaContent(doc, "og:type"),
+		URL:         metaContent(doc, "og:url"),
+		Image:       resolveMetaURL(pageURL, metaContent(doc, "og:image", "og:image:url", "og:image:secure_url")),
+		SiteName:    metaContent(doc, "og:site_name"),
+		Locale:      metaContent(doc, "og:locale"),
+	}
+	if openGraph != (models.OpenGraph{}) {
+		metadata.OpenGraph = &openGraph
+	}
+
+	twitter := models.TwitterCard{
+		Card:        metaContent(doc, "twitter:card"),
+		Title:       metaContent(doc, "twitter:title"),
+		Description: metaContent(doc, "twitter:description"),
+		Image:       resolveMetaURL(pageURL, metaContent(doc, "twitter:image", "twitter:image:src")),
+		Site:        metaContent(doc, "twitter:site"),
+		Creator:     metaContent(doc, "twitter:creator"),
+	}
+	if twitter != (models.TwitterCard{}) {
+		metadata.Twitter = &twitter
+	}
+
+	// 没有 <title> 或 meta description 时使用 OpenGraph 的值
+	if info.Title == "" {
+		info.Title = openGraph.Title
+	}
+	if info.Description == "" {
+		info.Description = openGraph.Description
+	}
+
+	return info
+}
+
+// metaContent 按优先级返回第一个匹配的 <meta> 的 content
+// 同时匹配 name、property 和 itemprop 属性（不区分大小写）
+func metaContent(doc *goquery.Document, keys ...string) string {
+	metas := doc.Find("meta[content]")
+	for _, key := range keys {
+		content := ""
+		metas.EachWithBreak(func(i int, s *goquery.Selection) bool {
+			for _, attr := range []string{"name", "property", "itemprop"} {
+				if strings.EqualFold(strings.TrimSpace(s.AttrOr(attr, "")), key) {
+					content = collapseSpace(s.AttrOr("content", ""))
+					return content == ""
+				}
+			}
+			return true
+		})
+		if content != "" {
+			return content
+		}
+	}
+	return ""
+}
+
+// timeDatetime 返回第一个匹配的 <time> 的 datetime（没有 datetime 时使用文本）
+func timeDatetime(doc *goquery.Document, selector string) string {
+	s := doc.Find(selector).First()
+	if s.Length() == 0 {
+		return ""
+	}
+	if datetime := strings.TrimSpace(s.AttrOr("datetime", "")); datetime != "" {
+		return datetime
+	}
+	return collapseSpace(s.Text())
+}
+
+// extractFavicon 返回页面声明的图标地址，优先使用 rel="icon"，其次是 apple-touch-icon
+func extractFavicon(doc *goquery.Document, pageURL string) string {
+	favicon, fallback := "", ""
+	doc.Find("link[rel][href]").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		rel := s.AttrOr("rel", "")
+		href := s.AttrOr("href", "")
+		switch {
+		case hasRelToken(rel, "icon"):
+			favicon = href
+			return false
+		case fallback == "" && (hasRelToken(rel, "apple-touch-icon") || hasRelToken(rel, "apple-touch-icon-precomposed")):
+			fallback = href
+		}
+		return true
+	})
+	if favicon == "" {
+		favicon = fallback
+	}
+	return resolveMetaURL(pageURL, favicon)
+}
+
+// resolveMetaURL 将元数据中的相对地址解析为绝对 URL
+func resolveMetaURL(pageURL, href string) string {
+	href = strings.TrimSpace(href)
+	if href == "" || pageURL == "" {
+		return href
+	}
+	absoluteURL, err := utils.ResolveURL(pageURL, href)
+	if err != nil {
+		return href
+	}
+	return absoluteURL
+}
+
+// normalizeDate 将可识别的日期统一为 RFC 3339（无时区的保持本地时间格式），无法识别时原样返回
+func normalizeDate(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+	for _, format := range dateLayouts {
+		if t, err := time.Parse(format.layout, value); err == nil {
+			return t.Format(format.output)
+		}
+	}
+	return value
+}
+
+// jsonLDObjects 解析页面中所有 application/ld+json 块，展开数组和 @graph 后返回其中的对象
+func jsonLDObjects(doc *goquery.Document) []map[string]interface{} {
+	var objects []map[string]interface{}
+
+	var collect func(value interface{})
+	collect = func(value interface{}) {
+		switch v := value.(type) {
+		case []interface{}:
+			for _, item := range v {
+				collect(item)
+			}
+		case map[string]interface{}:
+			objects = append(objects, v)
+			if graph, ok := v["@graph"]; ok {
+				collect(graph)
+			}
+		}
+	}
+
+	doc.Find(`script[type="application/ld+json"]`).Each(func(i int, s *goquery.Selection) {
+		var value interface{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(s.Text())), &value); err != nil {
+			return
+		}
+		collect(value)
+	})
+
+	return objects
+}
+
+// jsonLDString 返回第一个包含该字符串字段的 JSON-LD 对象中的值
+func jsonLDString(objects []map[string]interface{}, key string) string {
+	for _, object := range objects {
+		if value, ok := object[key].(string); ok && strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// jsonLDAuthor 返回 JSON-LD 中的作者名（author 可以是字符串、对象或数组）
+func jsonLDAuthor(objects []map[string]interface{}) string {
+	for _, object := range objects {
+		var names []string
+		var collect func(value interface{})
+		collect = func(value interface{}) {
+			switch v := value.(type) {
+			case string:
+				if name := collapseSpace(v); name != "" {
+					names = append(names, name)
+				}
+			case map[string]interface{}:
+				collect(v["name"])
+			case []interface{}:
+				for _, item := range v {
+					collect(item)
+				}
+			}
+		}
+		collect(object["author"])
+		if len(names) > 0 {
+			return strings.Join(names, ", ")
+		}
+	}
+	return ""
+}
+
+// firstNonEmpty 返回第一个非空字符串
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// collapseSpace 合并连续空白并去除首尾空白
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,126 @@
+package crawler
+
+import "testing"
+
+func TestExtractPageInfo(t *testing.T) {
+	html := `
+		<html>
+			<head>
+				<title>
+					Getting Started
+				</title>
+				<meta name="Description" content="Install and run the tool.">
+			</head>
+			<body>
+				<h1>Getting Started</h1>
+				<h1>Install</h1>
+			</body>
+		</html>
+	`
+
+	info := ExtractPageInfo(html, "https://example.com/docs/start")
+	if info.Title != "Getting Started" {
+		t.Errorf("Title = %q, want %q", info.Title, "Getting Started")
+	}
+	if info.Description != "Install and run the tool." {
+		t.Errorf("Description = %q", info.Description)
+	}
+	if len(info.H1) != 2 || info.H1[1] != "Install" {
+		t.Errorf("H1 = %v", info.H1)
+	}
+}
+
+func TestExtractPageInfo_Metadata(t *testing.T) {
+	html := `
+		<html lang="zh-CN">
+			<head>
+				<title>发布说明</title>
+				<meta name="author" content="Docs Team">
+				<meta property="article:published_time" content="2024-03-01T08:00:00+08:00">
+				<meta property="og:title" content="Release notes">
+				<meta property="og:type" content="article">
+				<meta property="og:image" content="/img/cover.png">
+				<meta name="twitter:card" content="summary_large_image">
+				<meta name="twitter:site" content="@example">
+				<link rel="apple-touch-icon" href="/apple.png">
+				<link rel="shortcut icon" href="/favicon.ico">
+				<script type="application/ld+json">
+					{"@context": "https://schema.org", "@graph": [
+						{"@type": "WebSite", "name": "Example"},
+						{"@type": "Article", "dateModified": "2024-03-05", "author": [{"name": "Alice"}, {"name": "Bob"}]}
+					]}
+				</script>
+			</head>
+			<body><h1>发布说明</h1></body>
+		</html>
+	`
+
+	metadata := ExtractPageInfo(html, "https://example.com/blog/release").Metadata
+	if metadata.Author != "Docs Team" {
+		t.Errorf("Author = %q", metadata.Author)
+	}
+	if metadata.Language != "zh-CN" {
+		t.Errorf("Language = %q", metadata.Language)
+	}
+	if metadata.Published != "2024-03-01T08:00:00+08:00" {
+		t.Errorf("Published = %q", metadata.Published)
+	}
+	if metadata.Modified != "2024-03-05" {
+		t.Errorf("Modified = %q", metadata.Modified)
+	}
+	if metadata.Favicon != "https://example.com/favicon.ico" {
+		t.Errorf("Favicon = %q", metadata.Favicon)
+	}
+	if metadata.OpenGraph == nil || metadata.OpenGraph.Title != "Release notes" || metadata.OpenGraph.Image != "https://example.com/img/cover.png" {
+		t.Errorf("OpenGraph = %+v", metadata.OpenGraph)
+	}
+	if metadata.Twitter == nil || metadata.Twitter.Card != "summary_large_image" || metadata.Twitter.Site != "@example" {
+		t.Errorf("Twitter = %+v", metadata.Twitter)
+	}
+}
+
+func TestExtractPageInfo_Fallbacks(t *testing.T) {
+	html := `
+		<html>
+			<head>
+				<meta property="og:title" content="Only OpenGraph">
+				<script type="application/ld+json">{"@type": "BlogPosting", "datePublished": "2023-12-24 10:30", "author": "Carol"}</script>
+			</head>
+			<body>
+				<article><time datetime="2020-01-01">Jan 1</time></article>
+			</body>
+		</html>
+	`
+
+	info := ExtractPageInfo(html, "https://example.com/")
+	if info.Title != "Only OpenGraph" {
+		t.Errorf("Title = %q, want OpenGraph title", info.Title)
+	}
+	if info.Metadata.Author != "Carol" {
+		t.Errorf("Author = %q, want JSON-LD author", info.Metadata.Author)
+	}
+	if info.Metadata.Published != "2023-12-24T10:30:00" {
+		t.Errorf("Published = %q, want JSON-LD date", info.Metadata.Published)
+	}
+	if info.Metadata.Twitter != nil {
+		t.Errorf("Twitter = %+v, want nil", info.Metadata.Twitter)
+	}
+}
+
+func TestNormalizeDate(t *testing.T) {
+	tests := map[string]string{
+		"2024-05-17T09:30:00Z":          "2024-05-17T09:30:00Z",
+		"2024-05-17T09:30:00.123+02:00": "2024-05-17T09:30:00+02:00",
+		"Fri, 17 May 2024 09:30:00 GMT": "2024-05-17T09:30:00Z",
+		"2024/05/17":                    "2024-05-17",
+		"2024年5月17日":                    "2024-05-17",
+		"last Tuesday":                  "last Tuesday",
+		"":                              "",
+	}
+
+	for value, expected := range tests {
+		if result := normalizeDate(value); result != expected {
+			t.Errorf("normalizeDate(%q) = %q, want %q", value, result, expected)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package crawler
+
+import (
+	"strings"
+
+	"flaremind/internal/profile"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// keepAttr 站点配置中需要保留的元素的临时标记，输出前会被移除
+const keepAttr = "data-flaremind-keep"
+
+// ExtractMainContentWithProfile 按站点配置提取主要内容
+// 先移除配置的 remove 元素并标记 keep 元素；配置的正文根元素存在时直接使用，否则回退到通用算法
+func (e *Extractor) ExtractMainContentWithProfile(html string, p *profile.Profile) (string, error) {
+	return e.extractWithProfile(html, p, nil)
+}
+
+// extractWithProfile 按站点配置提取主要内容，debug 不为 nil 时记录提取过程
+func (e *Extractor) extractWithProfile(html string, p *profile.Profile, debug *ExtractionDebug) (string, error) {
+	if p == nil {
+		return e.extractMainContent(html, debug)
+	}
+	if debug != nil {
+		debug.Profile = p.Name
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return "", err
+	}
+
+	for _, selector := range p.Remove {
+		removed := doc.Find(selector)
+		debug.recordRemoved(removed, "profile")
+		removed.Remove()
+	}
+	for _, selector := range p.Keep {
+		doc.Find(selector).SetAttr(keepAttr, "")
+	}
+
+	// 使用第一个有文本的正文根元素（匹配多个元素时合并）
+	for _, selector := range p.Content {
+		roots := doc.Find(selector)
+		if roots.Length() == 0 || textLength(roots.Text()) == 0 {
+			continue
+		}
+
+		attempt := debug.newAttempt("profile", nil)
+		attempt.recordChosen(roots.Get(0), roots.Nodes)
+		content := wrapNodes(roots.Nodes)
+		if content == nil {
+			break
+		}
+		noise := noiseElements(content, noiseSelector)
+		attempt.recordRemoved(noise, "noise")
+		noise.Remove()
+		e.cleanContent(content)
+		if attempt != nil {
+			attempt.TextLength = textLength(content.Text())
+		}
+		debug.selectAttempt(attempt)
+		return content.Html()
+	}
+
+	prepared, err := doc.Html()
+	if err != nil {
+		return "", err
+	}
+	return e.extractMainContent(prepared, debug)
+}
+
+// removeNoise 移除匹配选择器的元素，跳过被站点配置标记保留的元素及其祖先
+func removeNoise(s *goquery.Selection, selector string) {
+	noiseElements(s, selector).Remove()
+}
+
+// noiseElements 返回匹配选择器且未被站点配置标记保留的元素
+func noiseElements(s *goquery.Selection, selector string) *goquery.Selection {
+	return s.Find(selector).FilterFunction(func(i int, elem *goquery.Selection) bool {
+		return !isKept(elem)
+	})
+}
+
+// isKept 判断元素是否被标记保留或包含被标记保留的元素
+func isKept(s *goquery.Selection) bool {
+	if _, ok := s.Attr(keepAttr); ok {
+		return true
+	}
+	return s.Find("["+keepAttr+"]").Length() > 0
+}
+
+// applyProfileInfo 使用站点配置的标题和日期选择器覆盖通用提取的结果
+func applyProfileInfo(p *profile.Profile, html string, info *PageInfo) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return
+	}
+	if title := p.ExtractTitle(doc); title != "" {
+		info.Title = title
+	}
+	if date := p.ExtractDate(doc); date != "" {
+		info.Metadata.Published = normalizeDate(date)
+	}
+}
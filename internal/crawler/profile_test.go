@@ -0,0 +1,128 @@
+package crawler
+
+import (
+	"strings"
+	"testing"
+
+	"flaremind/internal/profile"
+)
+
+func mustParseProfile(t *testing.T, content string) *profile.Profile {
+	t.Helper()
+	p, err := profile.Parse([]byte(content))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return p
+}
+
+func TestExtractor_ExtractMainContentWithProfile(t *testing.T) {
+	extractor := NewExtractor()
+	p := mustParseProfile(t, "name: forum\nhosts: [forum.example.com]\ncontent: ['.post-body']\nremove: ['.signature']\n")
+
+	html := `
+		<html>
+			<body>
+				<article><p>Pinned announcement that the generic algorithm would pick.</p></article>
+				<div class="post-body"><p>First post.</p><p class="signature">Sent from my phone</p></div>
+				<div class="post-body"><p>Second post.</p></div>
+			</body>
+		</html>
+	`
+
+	result, err := extractor.ExtractMainContentWithProfile(html, p)
+	if err != nil {
+		t.Fatalf("ExtractMainContentWithProfile() error = %v", err)
+	}
+	if !strings.Contains(result, "First post.") || !strings.Contains(result, "Second post.") {
+		t.Errorf("expected all content roots to be merged, got %q", result)
+	}
+	if strings.Contains(result, "Pinned announcement") || strings.Contains(result, "Sent from my phone") {
+		t.Errorf("unexpected content outside the root or in removed elements: %q", result)
+	}
+}
+
+func TestExtractor_ProfileKeepsNoise(t *testing.T) {
+	extractor := NewExtractor()
+	p := mustParseProfile(t, "name: forum\nhosts: [forum.example.com]\nkeep: ['.comments']\n")
+
+	html := `
+		<html>
+			<body>
+				<nav>Navigation</nav>
+				<div class="thread">
+					<p>The original question asks how to configure retries, with backoff, for failed requests in the client.</p>
+					<p>The client is used from several services, and each of them talks to an upstream that fails occasionally, under load.</p>
+					<p>Today every failure surfaces to the user immediately, which is noisy, confusing, and mostly avoidable with a retry.</p>
+					<p>The asker wants to know which settings exist, what the defaults are, and whether retries are safe for writes.</p>
+					<p>They also ask whether the retry budget is shared between requests, or tracked separately for each request.</p>
+					<div class="comments">
+						<p>Answer: set the retry count in the config file and enable exponential backoff.</p>
+					</div>
+				</div>
+			</body>
+		</html>
+	`
+
+	result, err := extractor.ExtractMainContentWithProfile(html, p)
+	if err != nil {
+		t.Fatalf("ExtractMainContentWithProfile() error = %v", err)
+	}
+	if !strings.Contains(result, "exponential backoff") {
+		t.Errorf("kept comments should survive noise removal, got %q", result)
+	}
+	if strings.Contains(result, "Navigation") {
+		t.Errorf("navigation should still be removed, got %q", result)
+	}
+	if strings.Contains(result, keepAttr) {
+		t.Errorf("keep marker should be stripped from output, got %q", result)
+	}
+
+	// 没有配置时评论会被当作噪音移除（正文足够长，不会放宽清理条件）
+	generic, err := extractor.ExtractMainContent(html)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(generic, "exponential backoff") {
+		t.Errorf("generic extraction should drop comments, got %q", generic)
+	}
+}
+
+func TestExtractor_DocsPlatformProfile(t *testing.T) {
+	extractor := NewExtractor()
+	html := `
+		<html>
+			<head><meta name="generator" content="Docusaurus v3.1.0"></head>
+			<body>
+				<nav class="theme-doc-sidebar-menu"><a href="/docs/intro">Intro</a></nav>
+				<article>
+					<nav class="theme-doc-breadcrumbs"><a href="/docs">Docs</a></nav>
+					<div class="theme-doc-markdown markdown">
+						<h1>Installation</h1>
+						<p>Install the package with your package manager.</p>
+					</div>
+					<footer class="theme-doc-footer"><a class="theme-edit-this-page" href="#">Edit this page</a></footer>
+					<nav class="pagination-nav"><a href="/docs/next">Next</a></nav>
+				</article>
+			</body>
+		</html>
+	`
+
+	p := profile.NewSet(profile.Builtin()...).Match("https://docs.example.com/docs/install", html)
+	if p == nil || p.Platform != "docusaurus" {
+		t.Fatalf("Match() = %v, want the docusaurus profile", p)
+	}
+
+	result, err := extractor.ExtractMainContentWithProfile(html, p)
+	if err != nil {
+		t.Fatalf("ExtractMainContentWithProfile() error = %v", err)
+	}
+	if !strings.Contains(result, "Install the package") {
+		t.Errorf("expected the documentation body, got %q", result)
+	}
+	for _, chrome := range []string{"Edit this page", "Next", "Docs</a>"} {
+		if strings.Contains(result, chrome) {
+			t.Errorf("unexpected page chrome %q in %q", chrome, result)
+		}
+	}
+}
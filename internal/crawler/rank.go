@@ -0,0 +1,63 @@
+package crawler
+
+import (
+	"fmt"
+	"sort"
+
+	"flaremind/internal/models"
+)
+
+// 结果排序方式
+const (
+	SortByCrawl   = "crawl"   // 爬取顺序（默认）
+	SortByRank    = "rank"    // PageRank 从高到低
+	SortByInLinks = "inlinks" // 入链数从高到低
+	SortByDepth   = "depth"   // 深度从浅到深
+	SortByURL     = "url"     // URL 字典序
+	SortByNav     = "nav"     // 导航目录顺序（不在目录中的页面排在最后）
+)
+
+// SortResults 按指定方式对结果进行稳定排序
+func SortResults(results []models.PageResult, by string) error {
+	less, err := sortLess(by)
+	if err != nil || less == nil {
+		return err
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return less(results[i], results[j])
+	})
+	return nil
+}
+
+// ValidateSortOrder 检查排序方式是否有效
+func ValidateSortOrder(by string) error {
+	_, err := sortLess(by)
+	return err
+}
+
+// sortLess 返回排序方式的比较函数；按爬取顺序时返回 nil
+func sortLess(by string) (func(a, b models.PageResult) bool, error) {
+	var less func(a, b models.PageResult) bool
+	switch by {
+	case "", SortByCrawl:
+		return nil, nil
+	case SortByRank:
+		less = func(a, b models.PageResult) bool { return a.PageRank > b.PageRank }
+	case SortByInLinks:
+		less = func(a, b models.PageResult) bool { return a.InLinks > b.InLinks }
+	case SortByDepth:
+		less = func(a, b models.PageResult) bool { return a.Depth < b.Depth }
+	case SortByURL:
+		less = func(a, b models.PageResult) bool { return a.URL < b.URL }
+	case SortByNav:
+		less = func(a, b models.PageResult) bool {
+			if (a.NavOrder > 0) != (b.NavOrder > 0) {
+				return a.NavOrder > 0
+			}
+			return a.NavOrder < b.NavOrder
+		}
+	default:
+		return nil, fmt.Errorf("unknown sort order %q", by)
+	}
+	return less, nil
+}
@@ -0,0 +1,59 @@
+package crawler
+
+import (
+	"testing"
+
+	"flaremind/internal/models"
+)
+
+func TestSortResults(t *testing.T) {
+	results := []models.PageResult{
+		{URL: "https://example.com/b", Depth: 1, InLinks: 1, PageRank: 0.2, NavOrder: 2},
+		{URL: "https://example.com/a", Depth: 2, InLinks: 5, PageRank: 0.5},
+		{URL: "https://example.com/c", Depth: 0, InLinks: 0, PageRank: 0.3, NavOrder: 1},
+	}
+
+	tests := map[string]string{
+		SortByRank:    "https://example.com/a",
+		SortByInLinks: "https://example.com/a",
+		SortByDepth:   "https://example.com/c",
+		SortByURL:     "https://example.com/a",
+		SortByNav:     "https://example.com/c",
+	}
+
+	for by, first := range tests {
+		sorted := append([]models.PageResult(nil), results...)
+		if err := SortResults(sorted, by); err != nil {
+			t.Fatalf("SortResults(%s) error = %v", by, err)
+		}
+		if sorted[0].URL != first {
+			t.Errorf("SortResults(%s) first = %s, want %s", by, sorted[0].URL, first)
+		}
+	}
+
+	if err := SortResults(results, "size"); err == nil {
+		t.Error("Expected error for unknown sort order")
+	}
+	if err := ValidateSortOrder("size"); err == nil {
+		t.Error("Expected ValidateSortOrder to reject unknown sort order")
+	}
+	for _, by := range []string{"", SortByCrawl, SortByRank, SortByInLinks, SortByDepth, SortByURL, SortByNav} {
+		if err := ValidateSortOrder(by); err != nil {
+			t.Errorf("ValidateSortOrder(%q) error = %v", by, err)
+		}
+	}
+}
+
+func TestSortResults_NavPutsUnlistedLast(t *testing.T) {
+	results := []models.PageResult{
+		{URL: "https://example.com/blog"},
+		{URL: "https://example.com/docs/b", NavOrder: 2},
+		{URL: "https://example.com/docs/a", NavOrder: 1},
+	}
+	if err := SortResults(results, SortByNav); err != nil {
+		t.Fatal(err)
+	}
+	if results[0].NavOrder != 1 || results[1].NavOrder != 2 || results[2].URL != "https://example.com/blog" {
+		t.Errorf("SortResults(nav) = %v", results)
+	}
+}
@@ -0,0 +1,495 @@
+package crawler
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"flaremind/pkg/utils"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// readabilityOptions 单次 Readability 尝试的选项
+type readabilityOptions struct {
+	stripUnlikely      bool // 移除噪音元素和不太可能是正文的元素
+	weightClasses      bool // 根据 class/id 调整分数
+	cleanConditionally bool // 按链接密度等条件清理正文中的块
+}
+
+// readabilityAttempts 依次放宽清理条件的尝试顺序
+var readabilityAttempts = []readabilityOptions{
+	{stripUnlikely: true, weightClasses: true, cleanConditionally: true},
+	{stripUnlikely: false, weightClasses: true, cleanConditionally: true},
+	{stripUnlikely: false, weightClasses: false, cleanConditionally: true},
+	{stripUnlikely: false, weightClasses: false, cleanConditionally: false},
+}
+
+const (
+	readabilityCharThreshold      = 500 // 正文达到该长度即接受当前尝试的结果
+	readabilityMinParagraphLength = 25  // 参与评分的段落最小长度
+	readabilityLengthBonusChars   = 100 // 段落每达到该长度加 1 分
+	readabilitySiblingParagraph   = 80  // 兄弟段落按长段落或短句处理的分界长度
+	readabilityAncestorLevels     = 5   // 段落分数向上传播的层数
+	readabilityTopCandidates      = 5   // 用于判断公共祖先的候选数量
+	readabilityMinAlternatives    = 3   // 提升到公共祖先所需的候选数量
+
+	// cjkLengthFactor 中日韩文字单字信息量约为拉丁字母的 2.5 倍，长度阈值按该比例缩小
+	cjkLengthFactor = 0.4
+)
+
+// noiseSelector 始终被视为噪音的元素（保留 MathJax 2 保存 TeX 源码的 script）
+const noiseSelector = "script:not([type^='math/tex']), style, nav, header, footer, aside, .ad, .advertisement, .sidebar, .navigation, .menu, .social, .share, .comments, .comment, iframe, embed, object"
+
+// alwaysRemoveSelector 放宽清理条件时仍然移除的元素
+const alwaysRemoveSelector = "script:not([type^='math/tex']), style, noscript, template, iframe, embed, object"
+
+// divToParagraphSelector 包含这些元素的 div 不会被当作段落评分
+const divToParagraphSelector = "a, blockquote, dl, div, img, ol, p, pre, table, ul, select"
+
+var (
+	unlikelyCandidatesPattern = regexp.MustCompile(`(?i)-ad-|ai2html|banner|breadcrumbs|combx|comment|community|cover-wrap|disqus|extra|footer|gdpr|header|legends|menu|related|remark|replies|rss|shoutbox|sidebar|skyscraper|social|sponsor|supplemental|ad-break|agegate|pagination|pager|popup|yom-remote|newsletter|subscribe`)
+	maybeCandidatePattern     = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	positiveClassPattern      = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|pagination|post|text|blog|story`)
+	negativeClassPattern      = regexp.MustCompile(`(?i)-ad-|hidden|^hid$| hid$| hid |^hid |banner|combx|comment|com-|contact|foot|footer|footnote|gdpr|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget|newsletter|subscribe`)
+	sentenceEndPattern        = regexp.MustCompile(`\.( |$)|[。！？]`)
+)
+
+// readabilityResult 单次尝试的结果
+type readabilityResult struct {
+	article    *goquery.Selection
+	textLength int
+}
+
+// extractReadability 使用 Readability 候选评分算法提取正文
+// 依次放宽清理条件重试，返回第一个足够长的结果；都不够长时返回最长的结果。debug 不为 nil 时记录每次尝试
+func (e *Extractor) extractReadability(rawHTML string, debug *ExtractionDebug) (*goquery.Selection, bool) {
+	var best *readabilityResult
+	var bestAttempt *AttemptDebug
+
+	for _, options := range readabilityAttempts {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(rawHTML))
+		if err != nil {
+			return nil, false
+		}
+
+		attempt := debug.newAttempt("readability", &options)
+		article := e.grabArticle(doc, options, attempt)
+		if article == nil {
+			continue
+		}
+
+		text := article.Text()
+		result := &readabilityResult{article: article, textLength: textLength(text)}
+		if attempt != nil {
+			attempt.TextLength = result.textLength
+		}
+		if float64(result.textLength) >= scaledThreshold(readabilityCharThreshold, text) {
+			debug.selectAttempt(attempt)
+			return result.article, true
+		}
+		if best == nil || result.textLength > best.textLength {
+			best = result
+			bestAttempt = attempt
+		}
+	}
+
+	if best == nil || best.textLength == 0 {
+		return nil, false
+	}
+	debug.selectAttempt(bestAttempt)
+	return best.article, true
+}
+
+// grabArticle 单次 Readability 尝试：段落评分、分数传播、选择最佳候选并合并相关兄弟节点
+func (e *Extractor) grabArticle(doc *goquery.Document, options readabilityOptions, attempt *AttemptDebug) *goquery.Selection {
+	// 移除噪音元素
+	if options.stripUnlikely {
+		noise := noiseElements(doc.Selection, noiseSelector)
+		attempt.recordRemoved(noise, "noise")
+		noise.Remove()
+		removeUnlikelyCandidates(doc, attempt)
+	} else {
+		noise := doc.Find(alwaysRemoveSelector)
+		attempt.recordRemoved(noise, "noise")
+		noise.Remove()
+	}
+
+	scores := make(map[*html.Node]float64)
+	var candidates []*html.Node
+	factors := make(map[*html.Node]*ScoreFactors) // 仅在记录调试信息时使用
+
+	initialize := func(node *html.Node) {
+		if _, ok := scores[node]; ok {
+			return
+		}
+		tagScore, weight := tagBaseScore(node.Data), 0.0
+		if options.weightClasses {
+			weight = classWeight(goquery.NewDocumentFromNode(node).Selection)
+		}
+		scores[node] = tagScore + weight
+		candidates = append(candidates, node)
+		if attempt != nil {
+			factors[node] = &ScoreFactors{TagScore: tagScore, ClassWeight: weight}
+		}
+	}
+
+	// 段落：p、pre、td，以及不包含块级元素的 div
+	paragraphs := doc.Find("p, pre, td").AddSelection(doc.Find("div").FilterFunction(func(i int, s *goquery.Selection) bool {
+		return s.Find(divToParagraphSelector).Length() == 0
+	}))
+
+	paragraphs.Each(func(i int, s *goquery.Selection) {
+		text := strings.TrimSpace(s.Text())
+		length := textLength(text)
+		if float64(length) < scaledThreshold(readabilityMinParagraphLength, text) {
+			return
+		}
+
+		ancestors := elementAncestors(s.Get(0), readabilityAncestorLevels)
+		if len(ancestors) == 0 {
+			return
+		}
+
+		// 基础分 1，每个逗号（含中文逗号、顿号）加 1，每 100 个字符（中日韩文本按比例缩小）加 1（最多 3）
+		bonusChars := scaledThreshold(readabilityLengthBonusChars, text)
+		score := 1 + float64(utils.CountCommas(text)) + math.Min(math.Floor(float64(length)/bonusChars), 3)
+
+		for level, ancestor := range ancestors {
+			initialize(ancestor)
+			divider := 1.0
+			switch {
+			case level == 1:
+				divider = 2
+			case level > 1:
+				divider = float64(level) * 3
+			}
+			scores[ancestor] += score / divider
+			if attempt != nil {
+				factors[ancestor].ParagraphScore += score / divider
+				factors[ancestor].Paragraphs++
+			}
+		}
+	})
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	// 按链接密度调整分数
+	for _, candidate := range candidates {
+		density := linkDensity(goquery.NewDocumentFromNode(candidate).Selection)
+		scores[candidate] *= 1 - density
+		if attempt != nil {
+			factors[candidate].LinkDensity = density
+			factors[candidate].TextLength = textLength(goquery.NewDocumentFromNode(candidate).Text())
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return scores[candidates[i]] > scores[candidates[j]]
+	})
+	attempt.recordCandidates(candidates, scores, factors)
+	top := candidates[0]
+	if top.Data == "body" || top.Data == "html" {
+		return nil
+	}
+
+	top = promoteCandidate(top, candidates, scores)
+	topScore := scores[top]
+
+	// 合并分数足够高、内容像正文或被站点配置标记保留的兄弟节点
+	var parts []*html.Node
+	parent := top.Parent
+	if parent == nil || parent.Type != html.ElementNode {
+		parts = append(parts, top)
+	} else {
+		threshold := math.Max(10, topScore*0.2)
+		topClass := attrValue(top, "class")
+
+		for sibling := parent.FirstChild; sibling != nil; sibling = sibling.NextSibling {
+			if sibling.Type != html.ElementNode {
+				continue
+			}
+
+			include := sibling == top || isKept(goquery.NewDocumentFromNode(sibling).Selection)
+			if !include {
+				bonus := 0.0
+				if topClass != "" && attrValue(sibling, "class") == topClass {
+					bonus = topScore * 0.2
+				}
+				if score, ok := scores[sibling]; ok && score+bonus >= threshold {
+					include = true
+				} else if sibling.Data == "p" {
+					s := goquery.NewDocumentFromNode(sibling).Selection
+					text := strings.TrimSpace(s.Text())
+					length := textLength(text)
+					density := linkDensity(s)
+					boundary := scaledThreshold(readabilitySiblingParagraph, text)
+					if float64(length) > boundary && density < 0.25 {
+						include = true
+					} else if float64(length) < boundary && length > 0 && density == 0 && sentenceEndPattern.MatchString(text) {
+						include = true
+					}
+				}
+			}
+
+			if include {
+				parts = append(parts, sibling)
+			}
+		}
+	}
+
+	attempt.recordChosen(top, parts)
+
+	article := wrapNodes(parts)
+	if article == nil {
+		return nil
+	}
+
+	if options.cleanConditionally {
+		cleanConditionally(article, options.weightClasses, attempt)
+	}
+
+	return article
+}
+
+// removeUnlikelyCandidates 移除 class/id 看起来不像正文的元素
+func removeUnlikelyCandidates(doc *goquery.Document, attempt *AttemptDebug) {
+	doc.Find("body *").Each(func(i int, s *goquery.Selection) {
+		tag := goquery.NodeName(s)
+		if tag == "a" || tag == "body" || s.Closest("table, pre, code").Length() > 0 || isKept(s) {
+			return
+		}
+		matchString := s.AttrOr("class", "") + " " + s.AttrOr("id", "")
+		if unlikelyCandidatesPattern.MatchString(matchString) && !maybeCandidatePattern.MatchString(matchString) {
+			attempt.recordRemoved(s, "unlikely")
+			s.Remove()
+		}
+	})
+}
+
+// promoteCandidate 当多个高分候选共享同一祖先，或候选是父节点的唯一子元素时，提升到父节点
+func promoteCandidate(top *html.Node, candidates []*html.Node, scores map[*html.Node]float64) *html.Node {
+	topScore := scores[top]
+
+	var alternatives []*html.Node
+	for _, candidate := range candidates[1:] {
+		if len(alternatives) >= readabilityTopCandidates-1 {
+			break
+		}
+		if scores[candidate] >= topScore*0.75 {
+			alternatives = append(alternatives, candidate)
+		}
+	}
+
+	if len(alternatives) >= readabilityMinAlternatives {
+		for parent := top.Parent; parent != nil && parent.Type == html.ElementNode && parent.Data != "body"; parent = parent.Parent {
+			count := 0
+			for _, alternative := range alternatives {
+				if isAncestor(parent, alternative) {
+					count++
+				}
+			}
+			if count >= readabilityMinAlternatives {
+				top = parent
+				break
+			}
+		}
+	}
+
+	for {
+		parent := top.Parent
+		if parent == nil || parent.Type != html.ElementNode || parent.Data == "body" || elementChildCount(parent) != 1 {
+			return top
+		}
+		top = parent
+	}
+}
+
+// cleanConditionally 清理正文中链接密度过高、图片过多或内容过少的块
+func cleanConditionally(article *goquery.Selection, weightClasses bool, attempt *AttemptDebug) {
+	article.Find("form, fieldset, table, ul, ol, div, section").Each(func(i int, s *goquery.Selection) {
+		tag := goquery.NodeName(s)
+		if tag == "table" && isDataTable(s) {
+			return
+		}
+		if s.Find("pre, code").Length() > 0 || s.Closest("pre, code").Length() > 0 || isKept(s) {
+			return
+		}
+
+		weight := 0.0
+		if weightClasses {
+			weight = classWeight(s)
+		}
+		if weight < 0 {
+			attempt.recordRemoved(s, "conditional")
+			s.Remove()
+			return
+		}
+
+		text := strings.TrimSpace(s.Text())
+		if utils.CountCommas(text) >= 10 {
+			return
+		}
+
+		isList := tag == "ul" || tag == "ol"
+		paragraphs := s.Find("p").Length()
+		images := s.Find("img").Length()
+		listItems := s.Find("li").Length() - 100
+		inputs := s.Find("input").Length()
+		density := linkDensity(s)
+		length := textLength(text)
+
+		remove := (images > 1 && float64(paragraphs)/float64(images) < 0.5) ||
+			(!isList && listItems > paragraphs) ||
+			float64(inputs) > math.Floor(float64(paragraphs)/3) ||
+			(!isList && float64(length) < scaledThreshold(readabilityMinParagraphLength, text) && (images == 0 || images > 2)) ||
+			(weight < 25 && density > 0.2) ||
+			(weight >= 25 && density > 0.5)
+		if remove {
+			attempt.recordRemoved(s, "conditional")
+			s.Remove()
+		}
+	})
+}
+
+// isDataTable 判断表格是否为数据表格（而非布局表格）
+func isDataTable(s *goquery.Selection) bool {
+	if s.Find("th, thead, caption").Length() > 0 {
+		return true
+	}
+	return s.Find("td").Length() > 10
+}
+
+// tagBaseScore 按标签名给出候选节点的初始分数
+func tagBaseScore(tag string) float64 {
+	switch tag {
+	case "div", "article", "section", "main":
+		return 5
+	case "pre", "td", "blockquote":
+		return 3
+	case "address", "ol", "ul", "dl", "dd", "dt", "li", "form":
+		return -3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		return -5
+	}
+	return 0
+}
+
+// classWeight 根据 class 和 id 计算权重（正文特征 +25，噪音特征 -25）
+func classWeight(s *goquery.Selection) float64 {
+	weight := 0.0
+	for _, value := range []string{s.AttrOr("class", ""), s.AttrOr("id", "")} {
+		if value == "" {
+			continue
+		}
+		if negativeClassPattern.MatchString(value) {
+			weight -= 25
+		}
+		if positiveClassPattern.MatchString(value) {
+			weight += 25
+		}
+	}
+	return weight
+}
+
+// linkDensity 计算链接文本占全部文本的比例
+func linkDensity(s *goquery.Selection) float64 {
+	length := textLength(s.Text())
+	if length == 0 {
+		return 0
+	}
+	linkLength := 0
+	s.Find("a").Each(func(i int, a *goquery.Selection) {
+		linkLength += textLength(a.Text())
+	})
+	return float64(linkLength) / float64(length)
+}
+
+// textLength 计算去除首尾空白后的文本字符数（按字素计数，而非字节）
+func textLength(text string) int {
+	return utils.CharCount(strings.TrimSpace(text))
+}
+
+// scaledThreshold 按文本语言调整长度阈值：中日韩文字占比越高，阈值越小
+func scaledThreshold(threshold int, text string) float64 {
+	return float64(threshold) * (1 - utils.CJKRatio(text)*(1-cjkLengthFactor))
+}
+
+// elementAncestors 返回节点向上最多 levels 层的元素祖先（不含 html）
+func elementAncestors(node *html.Node, levels int) []*html.Node {
+	var ancestors []*html.Node
+	for parent := node.Parent; parent != nil && len(ancestors) < levels; parent = parent.Parent {
+		if parent.Type != html.ElementNode || parent.Data == "html" {
+			break
+		}
+		ancestors = append(ancestors, parent)
+	}
+	return ancestors
+}
+
+// isAncestor 判断 ancestor 是否为 node 的祖先
+func isAncestor(ancestor, node *html.Node) bool {
+	for parent := node.Parent; parent != nil; parent = parent.Parent {
+		if parent == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// elementChildCount 统计元素子节点数量
+func elementChildCount(node *html.Node) int {
+	count := 0
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode {
+			count++
+		}
+	}
+	return count
+}
+
+// attrValue 返回节点的属性值
+func attrValue(node *html.Node, key string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// wrapNodes 将节点的副本合并到一个新的 div 中（跳过祖先已包含在内的节点）
+func wrapNodes(nodes []*html.Node) *goquery.Selection {
+	var parts []string
+	for _, node := range nodes {
+		nested := false
+		for _, other := range nodes {
+			if other != node && isAncestor(other, node) {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			parts = append(parts, nodeOuterHTML(node))
+		}
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader("<div>" + strings.Join(parts, "") + "</div>"))
+	if err != nil {
+		return nil
+	}
+	return doc.Find("body > div").First()
+}
+
+// nodeOuterHTML 返回节点的 HTML（包含节点本身）
+func nodeOuterHTML(node *html.Node) string {
+	result, err := goquery.OuterHtml(goquery.NewDocumentFromNode(node).Selection)
+	if err != nil {
+		return ""
+	}
+	return result
+}
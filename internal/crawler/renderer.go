@@ -2,12 +2,17 @@ package crawler
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/chromedp"
 )
 
@@ -25,16 +30,34 @@ func NewRenderer(timeout time.Duration, headless bool) *Renderer {
 	}
 }
 
+// RenderResult 页面渲染结果（包含主文档的响应信息）
+type RenderResult struct {
+	HTML          string
+	FinalURL      string      // 跟随重定向后的最终 URL
+	StatusCode    int         // 主文档的 HTTP 状态码（未捕获到响应时为 0）
+	Header        http.Header // 主文档的响应头
+	RedirectChain []string    // 重定向经过的 URL（从请求的 URL 开始，不含最终 URL，无重定向时为空）
+}
+
 // Render 渲染页面并返回 HTML（带重试机制）
 func (r *Renderer) Render(url string) (string, error) {
+	result, err := r.RenderPage(url)
+	if err != nil {
+		return "", err
+	}
+	return result.HTML, nil
+}
+
+// RenderPage 渲染页面并返回 HTML 及主文档响应信息（带重试机制）
+func (r *Renderer) RenderPage(url string) (*RenderResult, error) {
 	ctx := context.Background()
 	retryConfig := DefaultRetryConfig()
 
-	var html string
+	var result *RenderResult
 	var lastErr error
 
 	err := Retry(ctx, func() error {
-		html, lastErr = r.renderPage(url)
+		result, lastErr = r.renderPage(url)
 		if lastErr != nil && IsRetryableError(lastErr) {
 			return lastErr
 		}
@@ -42,14 +65,14 @@ func (r *Renderer) Render(url string) (string, error) {
 	}, retryConfig)
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if lastErr != nil {
-		return "", lastErr
+		return nil, lastErr
 	}
 
-	return html, nil
+	return result, nil
 }
 
 // findChromePath 查找 Chrome 可执行文件路径
@@ -81,7 +104,7 @@ func findChromePath() string {
 }
 
 // renderPage 实际渲染页面的方法
-func (r *Renderer) renderPage(url string) (string, error) {
+func (r *Renderer) renderPage(url string) (*RenderResult, error) {
 	// 创建上下文
 	ctx, cancel := chromedp.NewContext(context.Background())
 	defer cancel()
@@ -113,6 +136,32 @@ func (r *Renderer) renderPage(url string) (string, error) {
 	defer cancel()
 
 	var html string
+	result := &RenderResult{FinalURL: url, Header: http.Header{}}
+
+	// 监听主文档的网络响应（第一个 Document 请求，重定向时 RequestID 不变）
+	var mu sync.Mutex
+	var mainRequestID network.RequestID
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch e := ev.(type) {
+		case *network.EventRequestWillBeSent:
+			if mainRequestID == "" && e.Type == network.ResourceTypeDocument {
+				mainRequestID = e.RequestID
+			}
+			// 同一请求的后续事件表示发生了重定向
+			if e.RequestID == mainRequestID && e.RedirectResponse != nil {
+				result.RedirectChain = append(result.RedirectChain, e.RedirectResponse.URL)
+			}
+		case *network.EventResponseReceived:
+			if e.RequestID != mainRequestID || e.Response == nil {
+				return
+			}
+			result.FinalURL = e.Response.URL
+			result.StatusCode = int(e.Response.Status)
+			result.Header = convertHeaders(e.Response.Headers)
+		}
+	})
 
 	// 执行任务
 	err := chromedp.Run(ctx,
@@ -132,8 +181,22 @@ func (r *Renderer) renderPage(url string) (string, error) {
 	)
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return html, nil
+	mu.Lock()
+	defer mu.Unlock()
+	result.HTML = html
+	return result, nil
+}
+
+// convertHeaders 将 CDP 响应头转换为 http.Header（多值头以换行分隔）
+func convertHeaders(headers network.Headers) http.Header {
+	h := http.Header{}
+	for key, value := range headers {
+		for _, v := range strings.Split(fmt.Sprint(value), "\n") {
+			h.Add(key, strings.TrimSpace(v))
+		}
+	}
+	return h
 }
@@ -0,0 +1,73 @@
+package crawler
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// RobotsDirectives 页面级 robots 指令
+type RobotsDirectives struct {
+	NoIndex  bool // 页面不应出现在结果中
+	NoFollow bool // 页面上的链接不应被跟随
+}
+
+// Merge 合并两组指令（任一方禁止即禁止）
+func (d RobotsDirectives) Merge(other RobotsDirectives) RobotsDirectives {
+	return RobotsDirectives{
+		NoIndex:  d.NoIndex || other.NoIndex,
+		NoFollow: d.NoFollow || other.NoFollow,
+	}
+}
+
+// ParseRobotsDirectives 解析逗号分隔的 robots 指令，例如 "noindex, nofollow"
+func ParseRobotsDirectives(value string) RobotsDirectives {
+	var directives RobotsDirectives
+	for _, token := range strings.Split(strings.ToLower(value), ",") {
+		switch strings.TrimSpace(token) {
+		case "noindex":
+			directives.NoIndex = true
+		case "nofollow":
+			directives.NoFollow = true
+		case "none":
+			directives.NoIndex = true
+			directives.NoFollow = true
+		}
+	}
+	return directives
+}
+
+// ExtractMetaRobots 从 <meta name="robots"> 中提取 robots 指令
+func ExtractMetaRobots(html string) RobotsDirectives {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return RobotsDirectives{}
+	}
+
+	var directives RobotsDirectives
+	doc.Find("meta[name][content]").Each(func(i int, s *goquery.Selection) {
+		if strings.EqualFold(strings.TrimSpace(s.AttrOr("name", "")), "robots") {
+			directives = directives.Merge(ParseRobotsDirectives(s.AttrOr("content", "")))
+		}
+	})
+
+	return directives
+}
+
+// XRobotsTagDirectives 从 X-Robots-Tag 响应头中提取 robots 指令
+// 带有爬虫名前缀的值（如 "googlebot: noindex"）只针对特定爬虫，予以忽略
+func XRobotsTagDirectives(values []string) RobotsDirectives {
+	var directives RobotsDirectives
+	for _, value := range values {
+		if name, _, found := strings.Cut(value, ":"); found && !strings.Contains(name, ",") {
+			continue
+		}
+		directives = directives.Merge(ParseRobotsDirectives(value))
+	}
+	return directives
+}
+
+// IsNoFollow 检查链接是否带有 rel="nofollow"、"ugc" 或 "sponsored"
+func (l Link) IsNoFollow() bool {
+	return hasRelToken(l.Rel, "nofollow") || hasRelToken(l.Rel, "ugc") || hasRelToken(l.Rel, "sponsored")
+}
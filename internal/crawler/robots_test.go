@@ -0,0 +1,40 @@
+package crawler
+
+import "testing"
+
+func TestExtractMetaRobots(t *testing.T) {
+	html := `<html><head><meta name="ROBOTS" content="noindex, follow"></head><body></body></html>`
+	directives := ExtractMetaRobots(html)
+	if !directives.NoIndex || directives.NoFollow {
+		t.Errorf("ExtractMetaRobots() = %+v, want noindex only", directives)
+	}
+
+	html = `<html><head><meta name="robots" content="none"></head></html>`
+	directives = ExtractMetaRobots(html)
+	if !directives.NoIndex || !directives.NoFollow {
+		t.Errorf("Expected \"none\" to imply noindex and nofollow, got %+v", directives)
+	}
+}
+
+func TestXRobotsTagDirectives(t *testing.T) {
+	directives := XRobotsTagDirectives([]string{"googlebot: noindex", "nofollow"})
+	if directives.NoIndex || !directives.NoFollow {
+		t.Errorf("XRobotsTagDirectives() = %+v, want nofollow only", directives)
+	}
+}
+
+func TestLinkIsNoFollow(t *testing.T) {
+	tests := map[string]bool{
+		"nofollow":            true,
+		"ugc noopener":        true,
+		"Sponsored":           true,
+		"noopener noreferrer": false,
+		"":                    false,
+	}
+
+	for rel, expected := range tests {
+		if result := (Link{Rel: rel}).IsNoFollow(); result != expected {
+			t.Errorf("Link{Rel: %q}.IsNoFollow() = %v, want %v", rel, result, expected)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package crawler
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxSitemapFiles 读取 sitemap 索引时最多访问的 sitemap 文件数
+const maxSitemapFiles = 50
+
+// sitemapDocument sitemap 文件（urlset 或 sitemapindex）
+type sitemapDocument struct {
+	XMLName  xml.Name
+	URLs     []sitemapLocation `xml:"url"`
+	Sitemaps []sitemapLocation `xml:"sitemap"`
+}
+
+type sitemapLocation struct {
+	Loc string `xml:"loc"`
+}
+
+// FetchSitemapURLs 读取 sitemap（支持 sitemap 索引和 .gz 压缩），返回其中列出的页面 URL
+func FetchSitemapURLs(ctx context.Context, sitemapURL string, timeout time.Duration) ([]string, error) {
+	client := &http.Client{Timeout: timeout}
+
+	var urls []string
+	seen := make(map[string]bool)
+	pending := []string{sitemapURL}
+	visited := make(map[string]bool)
+
+	for len(pending) > 0 && len(visited) < maxSitemapFiles {
+		current := pending[0]
+		pending = pending[1:]
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+
+		document, err := fetchSitemap(ctx, client, current)
+		if err != nil {
+			// 只有入口 sitemap 失败时返回错误，子 sitemap 失败时跳过
+			if current == sitemapURL {
+				return nil, err
+			}
+			continue
+		}
+
+		for _, location := range document.Sitemaps {
+			if loc := strings.TrimSpace(location.Loc); loc != "" {
+				pending = append(pending, loc)
+			}
+		}
+		for _, location := range document.URLs {
+			loc := strings.TrimSpace(location.Loc)
+			if loc != "" && !seen[loc] {
+				seen[loc] = true
+				urls = append(urls, loc)
+			}
+		}
+	}
+
+	return urls, nil
+}
+
+// fetchSitemap 下载并解析单个 sitemap 文件
+func fetchSitemap(ctx context.Context, client *http.Client, sitemapURL string) (*sitemapDocument, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sitemapURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("fetch sitemap %s: HTTP %d", sitemapURL, resp.StatusCode)
+	}
+
+	var body io.Reader = resp.Body
+	if strings.HasSuffix(strings.ToLower(req.URL.Path), ".gz") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("decompress sitemap %s: %w", sitemapURL, err)
+		}
+		defer gz.Close()
+		body = gz
+	}
+
+	var document sitemapDocument
+	if err := xml.NewDecoder(body).Decode(&document); err != nil {
+		return nil, fmt.Errorf("parse sitemap %s: %w", sitemapURL, err)
+	}
+
+	return &document, nil
+}
@@ -0,0 +1,46 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFetchSitemapURLs(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sitemap.xml":
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>%s/sitemap-docs.xml</loc></sitemap>
+  <sitemap><loc>%s/missing.xml</loc></sitemap>
+</sitemapindex>`, server.URL, server.URL)
+		case "/sitemap-docs.xml":
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc> https://example.com/docs </loc></url>
+  <url><loc>https://example.com/docs/intro</loc></url>
+  <url><loc>https://example.com/docs</loc></url>
+</urlset>`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	urls, err := FetchSitemapURLs(context.Background(), server.URL+"/sitemap.xml", 5*time.Second)
+	if err != nil {
+		t.Fatalf("FetchSitemapURLs() error = %v", err)
+	}
+	if len(urls) != 2 || urls[0] != "https://example.com/docs" || urls[1] != "https://example.com/docs/intro" {
+		t.Errorf("FetchSitemapURLs() = %v", urls)
+	}
+
+	if _, err := FetchSitemapURLs(context.Background(), server.URL+"/missing.xml", 5*time.Second); err == nil {
+		t.Error("Expected error for missing sitemap")
+	}
+}
@@ -0,0 +1,326 @@
+package crawler
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"flaremind/internal/profile"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ContentExtractor 正文提取策略：从渲染后的页面 HTML 中提取正文 HTML
+// 找不到正文时返回空字符串而不是错误，以便回退链继续尝试下一个策略
+type ContentExtractor interface {
+	ExtractContent(html string, pageURL string) (string, error)
+}
+
+// ExtractionExplainer 可以输出提取过程调试信息的正文提取策略（用于 -debug-extract）
+type ExtractionExplainer interface {
+	ExplainContent(html string, pageURL string) (*ExtractionDebug, error)
+}
+
+// 内置的正文提取策略
+const (
+	StrategyAuto        = "auto"        // Readability，找不到候选时回退到 heuristic
+	StrategyReadability = "readability" // 只使用 Readability 候选评分
+	StrategyHeuristic   = "heuristic"   // 按 article > main > 常见内容 class > body 选择内容区域
+	StrategyBody        = "body"        // 整个 body，只移除噪音元素
+	StrategyProfile     = "profile"     // 匹配的站点配置（没有匹配的配置时不提取）
+	StrategyRaw         = "raw"         // 整个 body，不做任何提取
+)
+
+// ExtractContent 实现 ContentExtractor（auto 策略）
+func (e *Extractor) ExtractContent(html string, pageURL string) (string, error) {
+	return e.ExtractMainContent(html)
+}
+
+// ExplainContent 实现 ExtractionExplainer（auto 策略）
+func (e *Extractor) ExplainContent(html string, pageURL string) (*ExtractionDebug, error) {
+	return e.ExplainExtraction(html, nil)
+}
+
+// NewStrategy 按名称创建正文提取策略，profiles 只用于 profile 策略
+func NewStrategy(name string, extractor *Extractor, profiles *profile.Set) (ContentExtractor, error) {
+	switch name {
+	case StrategyAuto:
+		return extractor, nil
+	case StrategyReadability:
+		return &readabilityStrategy{extractor: extractor}, nil
+	case StrategyHeuristic:
+		return &heuristicStrategy{extractor: extractor}, nil
+	case StrategyBody:
+		return &bodyStrategy{extractor: extractor}, nil
+	case StrategyProfile:
+		return &profileStrategy{extractor: extractor, profiles: profiles}, nil
+	case StrategyRaw:
+		return rawStrategy{}, nil
+	}
+	return nil, fmt.Errorf("unknown extraction strategy %q (expected auto, readability, heuristic, body, profile or raw)", name)
+}
+
+// ParseStrategy 解析以逗号分隔的策略列表，多个策略组成回退链（见 ChainExtractor）
+func ParseStrategy(spec string, extractor *Extractor, profiles *profile.Set, minChars int) (ContentExtractor, error) {
+	var strategies []ContentExtractor
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		strategy, err := NewStrategy(name, extractor, profiles)
+		if err != nil {
+			return nil, err
+		}
+		strategies = append(strategies, strategy)
+	}
+
+	switch len(strategies) {
+	case 0:
+		return nil, fmt.Errorf("empty extraction strategy")
+	case 1:
+		if minChars <= 0 {
+			return strategies[0], nil
+		}
+	}
+	return &ChainExtractor{Strategies: strategies, MinChars: minChars}, nil
+}
+
+// ParseExtractionRule 解析 "正则=策略[,策略...]" 形式的按 URL 选择策略的规则
+func ParseExtractionRule(spec string, extractor *Extractor, profiles *profile.Set, minChars int) (ExtractionRule, error) {
+	index := strings.LastIndex(spec, "=")
+	if index <= 0 {
+		return ExtractionRule{}, fmt.Errorf("invalid extraction rule %q (expected PATTERN=STRATEGY[,STRATEGY...])", spec)
+	}
+	pattern, err := regexp.Compile(spec[:index])
+	if err != nil {
+		return ExtractionRule{}, fmt.Errorf("invalid extraction rule %q: %v", spec, err)
+	}
+	strategy, err := ParseStrategy(spec[index+1:], extractor, profiles, minChars)
+	if err != nil {
+		return ExtractionRule{}, fmt.Errorf("invalid extraction rule %q: %v", spec, err)
+	}
+	return ExtractionRule{Pattern: pattern, Extractor: strategy}, nil
+}
+
+// ChainExtractor 依次尝试多个策略，使用第一个正文达到 MinChars 个字符的结果
+// MinChars 为 0 时使用第一个非空结果；都不够长时使用最长的结果
+type ChainExtractor struct {
+	Strategies []ContentExtractor
+	MinChars   int
+}
+
+// ExtractContent 实现 ContentExtractor
+func (c *ChainExtractor) ExtractContent(html string, pageURL string) (string, error) {
+	_, content, err := c.pick(html, pageURL)
+	return content, err
+}
+
+// ExplainContent 实现 ExtractionExplainer：解释被采用的策略
+func (c *ChainExtractor) ExplainContent(html string, pageURL string) (*ExtractionDebug, error) {
+	index, _, err := c.pick(html, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 {
+		return &ExtractionDebug{Selected: -1}, nil
+	}
+	return explainStrategy(c.Strategies[index], html, pageURL)
+}
+
+// pick 返回被采用的策略下标及其结果（所有策略都没有结果时下标为 -1）
+func (c *ChainExtractor) pick(html string, pageURL string) (int, string, error) {
+	best, bestContent, bestLength := -1, "", 0
+	var lastErr error
+	for i, strategy := range c.Strategies {
+		content, err := strategy.ExtractContent(html, pageURL)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		length := contentLength(content)
+		if length == 0 {
+			continue
+		}
+		if length >= c.MinChars {
+			return i, content, nil
+		}
+		if length > bestLength {
+			best, bestContent, bestLength = i, content, length
+		}
+	}
+	if best < 0 && lastErr != nil {
+		return -1, "", lastErr
+	}
+	return best, bestContent, nil
+}
+
+// ExtractionRule 按 URL 选择正文提取策略的规则
+type ExtractionRule struct {
+	Pattern   *regexp.Regexp
+	Extractor ContentExtractor
+}
+
+// RouteExtractor 按页面 URL 选择正文提取策略：使用第一条匹配的规则，都不匹配时使用 Default
+type RouteExtractor struct {
+	Rules   []ExtractionRule
+	Default ContentExtractor
+}
+
+// ExtractContent 实现 ContentExtractor
+func (r *RouteExtractor) ExtractContent(html string, pageURL string) (string, error) {
+	return r.route(pageURL).ExtractContent(html, pageURL)
+}
+
+// ExplainContent 实现 ExtractionExplainer
+func (r *RouteExtractor) ExplainContent(html string, pageURL string) (*ExtractionDebug, error) {
+	return explainStrategy(r.route(pageURL), html, pageURL)
+}
+
+// route 返回页面使用的策略
+func (r *RouteExtractor) route(pageURL string) ContentExtractor {
+	for _, rule := range r.Rules {
+		if rule.Pattern.MatchString(pageURL) {
+			return rule.Extractor
+		}
+	}
+	return r.Default
+}
+
+// explainStrategy 调用策略的 ExplainContent，不支持时返回不含尝试记录的调试信息
+func explainStrategy(strategy ContentExtractor, html string, pageURL string) (*ExtractionDebug, error) {
+	if explainer, ok := strategy.(ExtractionExplainer); ok {
+		return explainer.ExplainContent(html, pageURL)
+	}
+	return &ExtractionDebug{Selected: -1}, nil
+}
+
+// contentLength 返回正文 HTML 的文本字符数
+func contentLength(content string) int {
+	if content == "" {
+		return 0
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		return 0
+	}
+	return textLength(doc.Text())
+}
+
+// readabilityStrategy 只使用 Readability 候选评分，找不到候选时不提取
+type readabilityStrategy struct {
+	extractor *Extractor
+}
+
+func (s *readabilityStrategy) ExtractContent(html string, pageURL string) (string, error) {
+	return s.extract(html, nil)
+}
+
+func (s *readabilityStrategy) ExplainContent(html string, pageURL string) (*ExtractionDebug, error) {
+	return explain(html, func(source string, debug *ExtractionDebug) error {
+		_, err := s.extract(source, debug)
+		return err
+	})
+}
+
+func (s *readabilityStrategy) extract(html string, debug *ExtractionDebug) (string, error) {
+	content, ok := s.extractor.extractReadability(html, debug)
+	if !ok {
+		return "", nil
+	}
+	s.extractor.cleanContent(content)
+	return content.Html()
+}
+
+// heuristicStrategy 按内容区域整体评分选择正文
+type heuristicStrategy struct {
+	extractor *Extractor
+}
+
+func (s *heuristicStrategy) ExtractContent(html string, pageURL string) (string, error) {
+	return s.extract(html, nil)
+}
+
+func (s *heuristicStrategy) ExplainContent(html string, pageURL string) (*ExtractionDebug, error) {
+	return explain(html, func(source string, debug *ExtractionDebug) error {
+		_, err := s.extract(source, debug)
+		return err
+	})
+}
+
+func (s *heuristicStrategy) extract(html string, debug *ExtractionDebug) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return "", err
+	}
+	attempt := debug.newAttempt(StrategyHeuristic, nil)
+	content := s.extractor.extractHeuristic(doc, attempt)
+	debug.selectAttempt(attempt)
+	s.extractor.cleanContent(content)
+	return content.Html()
+}
+
+// bodyStrategy 使用整个 body，只移除噪音元素
+type bodyStrategy struct {
+	extractor *Extractor
+}
+
+func (s *bodyStrategy) ExtractContent(html string, pageURL string) (string, error) {
+	return s.extract(html, nil)
+}
+
+func (s *bodyStrategy) ExplainContent(html string, pageURL string) (*ExtractionDebug, error) {
+	return explain(html, func(source string, debug *ExtractionDebug) error {
+		_, err := s.extract(source, debug)
+		return err
+	})
+}
+
+func (s *bodyStrategy) extract(html string, debug *ExtractionDebug) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return "", err
+	}
+	attempt := debug.newAttempt(StrategyBody, nil)
+	body := doc.Find("body")
+	noise := noiseElements(body, noiseSelector)
+	attempt.recordRemoved(noise, "noise")
+	noise.Remove()
+	attempt.recordChosen(body.Get(0), nil)
+	debug.selectAttempt(attempt)
+	s.extractor.cleanContent(body)
+	return body.Html()
+}
+
+// profileStrategy 使用匹配的站点配置提取正文，没有匹配的配置时不提取
+type profileStrategy struct {
+	extractor *Extractor
+	profiles  *profile.Set
+}
+
+func (s *profileStrategy) ExtractContent(html string, pageURL string) (string, error) {
+	p := s.profiles.Match(pageURL, html)
+	if p == nil {
+		return "", nil
+	}
+	return s.extractor.ExtractMainContentWithProfile(html, p)
+}
+
+func (s *profileStrategy) ExplainContent(html string, pageURL string) (*ExtractionDebug, error) {
+	p := s.profiles.Match(pageURL, html)
+	if p == nil {
+		return &ExtractionDebug{Selected: -1}, nil
+	}
+	return s.extractor.ExplainExtraction(html, p)
+}
+
+// rawStrategy 原样使用整个 body
+type rawStrategy struct{}
+
+func (rawStrategy) ExtractContent(html string, pageURL string) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return "", err
+	}
+	return doc.Find("body").Html()
+}
@@ -0,0 +1,132 @@
+package crawler
+
+import (
+	"strings"
+	"testing"
+
+	"flaremind/internal/profile"
+)
+
+const strategyTestPage = `
+	<html>
+		<body>
+			<nav><a href="/">Home</a></nav>
+			<div class="post-body"><p>Forum post body.</p></div>
+			<article>
+				<p>The article explains the topic in detail, with commas, clauses, and enough text to be scored as a paragraph.</p>
+				<p>The second paragraph continues the explanation, adding more context, examples, and yet another clause.</p>
+			</article>
+			<script>var tracking = true;</script>
+		</body>
+	</html>
+`
+
+func TestNewStrategy(t *testing.T) {
+	extractor := NewExtractor()
+	profiles := profile.NewSet(mustParseProfile(t, "name: forum\nhosts: [forum.example.com]\ncontent: ['.post-body']\n"))
+
+	tests := []struct {
+		name    string
+		url     string
+		want    []string
+		exclude []string
+	}{
+		{StrategyAuto, "https://example.com/a", []string{"explains the topic"}, []string{"Home", "Forum post body"}},
+		{StrategyReadability, "https://example.com/a", []string{"explains the topic"}, []string{"Home"}},
+		{StrategyHeuristic, "https://example.com/a", []string{"explains the topic"}, []string{"Forum post body"}},
+		{StrategyBody, "https://example.com/a", []string{"Forum post body", "explains the topic"}, []string{"Home", "tracking"}},
+		{StrategyProfile, "https://forum.example.com/t/1", []string{"Forum post body"}, []string{"explains the topic"}},
+		{StrategyRaw, "https://example.com/a", []string{"Home", "Forum post body", "tracking"}, nil},
+	}
+
+	for _, tt := range tests {
+		strategy, err := NewStrategy(tt.name, extractor, profiles)
+		if err != nil {
+			t.Fatalf("NewStrategy(%s) error = %v", tt.name, err)
+		}
+		content, err := strategy.ExtractContent(strategyTestPage, tt.url)
+		if err != nil {
+			t.Fatalf("%s: ExtractContent() error = %v", tt.name, err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(content, want) {
+				t.Errorf("%s: expected %q in %q", tt.name, want, content)
+			}
+		}
+		for _, unwanted := range tt.exclude {
+			if strings.Contains(content, unwanted) {
+				t.Errorf("%s: unexpected %q in %q", tt.name, unwanted, content)
+			}
+		}
+	}
+
+	// 没有匹配的站点配置时不提取
+	strategy, _ := NewStrategy(StrategyProfile, extractor, profiles)
+	if content, err := strategy.ExtractContent(strategyTestPage, "https://example.com/a"); err != nil || content != "" {
+		t.Errorf("profile strategy without a match = %q, %v, want empty", content, err)
+	}
+
+	if _, err := NewStrategy("magic", extractor, profiles); err == nil {
+		t.Error("expected an error for an unknown strategy")
+	}
+}
+
+func TestChainExtractor(t *testing.T) {
+	extractor := NewExtractor()
+	profiles := profile.NewSet(mustParseProfile(t, "name: forum\nhosts: [forum.example.com]\ncontent: ['.post-body']\n"))
+
+	// profile 策略没有匹配时回退到下一个策略
+	chain, err := ParseStrategy("profile, readability", extractor, profiles, 0)
+	if err != nil {
+		t.Fatalf("ParseStrategy() error = %v", err)
+	}
+	if content, _ := chain.ExtractContent(strategyTestPage, "https://example.com/a"); !strings.Contains(content, "explains the topic") {
+		t.Errorf("chain without a profile match = %q", content)
+	}
+	if content, _ := chain.ExtractContent(strategyTestPage, "https://forum.example.com/t/1"); !strings.Contains(content, "Forum post body") {
+		t.Errorf("chain with a profile match = %q", content)
+	}
+
+	// 结果短于 MinChars 时回退
+	chain, _ = ParseStrategy("profile,readability", extractor, profiles, 50)
+	if content, _ := chain.ExtractContent(strategyTestPage, "https://forum.example.com/t/1"); !strings.Contains(content, "explains the topic") {
+		t.Errorf("chain with a short profile result = %q, want the readability result", content)
+	}
+
+	// 都不够长时使用最长的结果
+	chain, _ = ParseStrategy("profile,heuristic", extractor, profiles, 10000)
+	if content, _ := chain.ExtractContent(strategyTestPage, "https://forum.example.com/t/1"); !strings.Contains(content, "explains the topic") {
+		t.Errorf("chain with only short results = %q, want the longest result", content)
+	}
+
+	if _, err := ParseStrategy(" , ", extractor, profiles, 0); err == nil {
+		t.Error("expected an error for an empty strategy list")
+	}
+}
+
+func TestRouteExtractor(t *testing.T) {
+	extractor := NewExtractor()
+	rule, err := ParseExtractionRule(`/forum/=body`, extractor, nil, 0)
+	if err != nil {
+		t.Fatalf("ParseExtractionRule() error = %v", err)
+	}
+	router := &RouteExtractor{Rules: []ExtractionRule{rule}, Default: extractor}
+
+	if content, _ := router.ExtractContent(strategyTestPage, "https://example.com/forum/1"); !strings.Contains(content, "Forum post body") {
+		t.Errorf("routed content = %q, want the body strategy result", content)
+	}
+	if content, _ := router.ExtractContent(strategyTestPage, "https://example.com/news/1"); strings.Contains(content, "Forum post body") {
+		t.Errorf("default content = %q, want the auto strategy result", content)
+	}
+
+	debug, err := router.ExplainContent(strategyTestPage, "https://example.com/forum/1")
+	if err != nil || debug.Selected < 0 || debug.Attempts[debug.Selected].Method != StrategyBody {
+		t.Errorf("ExplainContent() = %+v, %v, want the body strategy explained", debug, err)
+	}
+
+	for _, spec := range []string{"no-strategy", "(=body", "/a/=magic"} {
+		if _, err := ParseExtractionRule(spec, extractor, nil, 0); err == nil {
+			t.Errorf("ParseExtractionRule(%q) expected an error", spec)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package crawler
+
+import (
+	"strings"
+
+	"flaremind/internal/models"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// schemaOrgPrefixes 规范化类型名时去除的 schema.org 前缀
+var schemaOrgPrefixes = []string{
+	"http://schema.org/", "https://schema.org/", "http://www.schema.org/", "https://www.schema.org/", "schema:",
+}
+
+// ExtractStructuredData 从渲染后的 HTML 中提取 JSON-LD、Microdata 和 RDFa 数据，按 @type 分组
+// 类型名去除 schema.org 前缀；声明多个类型的对象会出现在每个类型的分组中；没有类型的对象不会返回
+func ExtractStructuredData(rawHTML string, pageURL string) models.StructuredData {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(rawHTML))
+	if err != nil {
+		return nil
+	}
+
+	var items []map[string]interface{}
+	for _, object := range jsonLDObjects(doc) {
+		if _, ok := object["@type"]; ok {
+			items = append(items, normalizeJSONLD(object).(map[string]interface{}))
+		}
+	}
+	items = append(items, extractMicrodata(doc, pageURL)...)
+	items = append(items, extractRDFa(doc, pageURL)...)
+
+	data := make(models.StructuredData)
+	for _, item := range items {
+		for _, itemType := range schemaTypes(item["@type"]) {
+			data[itemType] = append(data[itemType], item)
+		}
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	return data
+}
+
+// normalizeJSONLD 递归规范化 JSON-LD 值：去除 @context，并规范化所有 @type
+func normalizeJSONLD(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			switch key {
+			case "@context":
+				continue
+			case "@type":
+				types := schemaTypes(item)
+				if len(types) == 1 {
+					object[key] = types[0]
+				} else {
+					object[key] = stringsToInterfaces(types)
+				}
+			default:
+				object[key] = normalizeJSONLD(item)
+			}
+		}
+		return object
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = normalizeJSONLD(item)
+		}
+		return items
+	}
+	return value
+}
+
+// extractMicrodata 提取顶层 itemscope 元素（不是其他条目的属性）
+func extractMicrodata(doc *goquery.Document, pageURL string) []map[string]interface{} {
+	var items []map[string]interface{}
+	doc.Find("[itemscope]").Each(func(i int, s *goquery.Selection) {
+		if _, isProperty := s.Attr("itemprop"); isProperty && s.ParentsFiltered("[itemscope]").Length() > 0 {
+			return
+		}
+		items = append(items, microdataItem(s.Get(0), pageURL))
+	})
+	return items
+}
+
+// microdataItem 将 itemscope 元素转换为 JSON-LD 形式的对象
+func microdataItem(node *html.Node, pageURL string) map[string]interface{} {
+	item := make(map[string]interface{})
+	if types := strings.Fields(attrValue(node, "itemtype")); len(types) > 0 {
+		types = schemaTypes(stringsToInterfaces(types))
+		if len(types) == 1 {
+			item["@type"] = types[0]
+		} else {
+			item["@type"] = stringsToInterfaces(types)
+		}
+	}
+	if id := attrValue(node, "itemid"); id != "" {
+		item["@id"] = id
+	}
+
+	walkScope(node, func(child *html.Node) bool {
+		_, nested := nodeAttr(child, "itemscope")
+		if names := strings.Fields(attrValue(child, "itemprop")); len(names) > 0 {
+			var value interface{}
+			if nested {
+				value = microdataItem(child, pageURL)
+			} else {
+				value = microdataValue(child, pageURL)
+			}
+			for _, name := range names {
+				addProperty(item, name, value)
+			}
+		}
+		return !nested
+	})
+
+	return item
+}
+
+// microdataValue 按元素类型取 itemprop 的值
+func microdataValue(node *html.Node, pageURL string) interface{} {
+	if content, ok := nodeAttr(node, "content"); ok {
+		return strings.TrimSpace(content)
+	}
+	switch node.Data {
+	case "audio", "embed", "iframe", "img", "source", "track", "video":
+		return resolveMetaURL(pageURL, attrValue(node, "src"))
+	case "a", "area", "link":
+		return resolveMetaURL(pageURL, attrValue(node, "href"))
+	case "object":
+		return resolveMetaURL(pageURL, attrValue(node, "data"))
+	case "data", "meter":
+		return strings.TrimSpace(attrValue(node, "value"))
+	case "time":
+		if datetime := strings.TrimSpace(attrValue(node, "datetime")); datetime != "" {
+			return datetime
+		}
+	}
+	return collapseSpace(goquery.NewDocumentFromNode(node).Text())
+}
+
+// extractRDFa 提取顶层 typeof 元素（基本 RDFa：typeof、property、content、resource）
+func extractRDFa(doc *goquery.Document, pageURL string) []map[string]interface{} {
+	var items []map[string]interface{}
+	doc.Find("[typeof]").Each(func(i int, s *goquery.Selection) {
+		if _, isProperty := s.Attr("property"); isProperty && s.ParentsFiltered("[typeof]").Length() > 0 {
+			return
+		}
+		items = append(items, rdfaItem(s.Get(0), pageURL))
+	})
+	return items
+}
+
+// rdfaItem 将 typeof 元素转换为 JSON-LD 形式的对象
+func rdfaItem(node *html.Node, pageURL string) map[string]interface{} {
+	item := make(map[string]interface{})
+	types := schemaTypes(stringsToInterfaces(strings.Fields(attrValue(node, "typeof"))))
+	if len(types) == 1 {
+		item["@type"] = types[0]
+	} else if len(types) > 1 {
+		item["@type"] = stringsToInterfaces(types)
+	}
+	if id := firstNonEmpty(attrValue(node, "resource"), attrValue(node, "about")); id != "" {
+		item["@id"] = resolveMetaURL(pageURL, id)
+	}
+
+	walkScope(node, func(child *html.Node) bool {
+		_, nested := nodeAttr(child, "typeof")
+		if names := strings.Fields(attrValue(child, "property")); len(names) > 0 {
+			var value interface{}
+			if nested {
+				value = rdfaItem(child, pageURL)
+			} else {
+				value = rdfaValue(child, pageURL)
+			}
+			for _, name := range names {
+				addProperty(item, schemaTypeName(name), value)
+			}
+		}
+		return !nested
+	})
+
+	return item
+}
+
+// rdfaValue 按 content、resource/href/src、datetime、文本的顺序取 property 的值
+func rdfaValue(node *html.Node, pageURL string) interface{} {
+	if content, ok := nodeAttr(node, "content"); ok {
+		return strings.TrimSpace(content)
+	}
+	for _, key := range []string{"resource", "href", "src"} {
+		if value := attrValue(node, key); value != "" {
+			return resolveMetaURL(pageURL, value)
+		}
+	}
+	if datetime := strings.TrimSpace(attrValue(node, "datetime")); datetime != "" {
+		return datetime
+	}
+	return collapseSpace(goquery.NewDocumentFromNode(node).Text())
+}
+
+// walkScope 深度优先遍历节点的后代元素，visit 返回 false 时不再进入该元素的子节点
+func walkScope(node *html.Node, visit func(child *html.Node) bool) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode {
+			continue
+		}
+		if visit(child) {
+			walkScope(child, visit)
+		}
+	}
+}
+
+// addProperty 添加属性值，同名属性出现多次时转换为数组
+func addProperty(item map[string]interface{}, name string, value interface{}) {
+	existing, ok := item[name]
+	if !ok {
+		item[name] = value
+		return
+	}
+	if values, isList := existing.([]interface{}); isList {
+		item[name] = append(values, value)
+		return
+	}
+	item[name] = []interface{}{existing, value}
+}
+
+// schemaTypes 返回规范化后的类型名列表（@type 可以是字符串或数组）
+func schemaTypes(value interface{}) []string {
+	var types []string
+	switch v := value.(type) {
+	case string:
+		if name := schemaTypeName(v); name != "" {
+			types = append(types, name)
+		}
+	case []interface{}:
+		for _, item := range v {
+			types = append(types, schemaTypes(item)...)
+		}
+	}
+	return types
+}
+
+// schemaTypeName 去除 schema.org 前缀，例如 https://schema.org/Product -> Product
+func schemaTypeName(name string) string {
+	name = strings.TrimSpace(name)
+	for _, prefix := range schemaOrgPrefixes {
+		if strings.HasPrefix(strings.ToLower(name), prefix) {
+			return name[len(prefix):]
+		}
+	}
+	return name
+}
+
+// stringsToInterfaces 将字符串切片转换为 JSON 数组值
+func stringsToInterfaces(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
+
+// nodeAttr 返回节点属性值及是否存在
+func nodeAttr(node *html.Node, key string) (string, bool) {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,135 @@
+package crawler
+
+import "testing"
+
+func TestExtractStructuredData_JSONLD(t *testing.T) {
+	html := `
+		<html>
+			<head>
+				<script type="application/ld+json">
+					{"@context": "https://schema.org", "@graph": [
+						{"@type": "Article", "headline": "Release notes", "author": {"@type": "Person", "name": "Alice"}},
+						{"@type": "BreadcrumbList", "itemListElement": [
+							{"@type": "ListItem", "position": 1, "name": "Docs"},
+							{"@type": "ListItem", "position": 2, "name": "Releases"}
+						]}
+					]}
+				</script>
+				<script type="application/ld+json">
+					{"@context": "https://schema.org", "@type": ["FAQPage", "WebPage"], "mainEntity": []}
+				</script>
+				<script type="application/ld+json">not json</script>
+			</head>
+			<body></body>
+		</html>
+	`
+
+	data := ExtractStructuredData(html, "https://example.com/")
+
+	articles := data["Article"]
+	if len(articles) != 1 || articles[0]["headline"] != "Release notes" {
+		t.Fatalf("Article = %v", articles)
+	}
+	if _, ok := articles[0]["@context"]; ok {
+		t.Error("@context should be removed")
+	}
+	author, ok := articles[0]["author"].(map[string]interface{})
+	if !ok || author["@type"] != "Person" || author["name"] != "Alice" {
+		t.Errorf("author = %v", articles[0]["author"])
+	}
+	if len(data["BreadcrumbList"]) != 1 {
+		t.Errorf("BreadcrumbList = %v", data["BreadcrumbList"])
+	}
+	if len(data["FAQPage"]) != 1 || len(data["WebPage"]) != 1 {
+		t.Errorf("multi-typed object should be grouped under each type, got %v", data)
+	}
+	if _, ok := data["ListItem"]; ok {
+		t.Error("nested objects should not be grouped separately")
+	}
+}
+
+func TestExtractStructuredData_Microdata(t *testing.T) {
+	html := `
+		<html>
+			<body>
+				<div itemscope itemtype="https://schema.org/Product" itemid="#p1">
+					<h1 itemprop="name">Widget</h1>
+					<img itemprop="image" src="/img/widget.png">
+					<a itemprop="url" href="/products/widget">Widget</a>
+					<span itemprop="category">Tools</span>
+					<span itemprop="category">Hardware</span>
+					<div itemprop="offers" itemscope itemtype="http://schema.org/Offer">
+						<meta itemprop="priceCurrency" content="USD">
+						<span itemprop="price" content="9.99">$9.99</span>
+						<span itemprop="name">Offer name</span>
+					</div>
+				</div>
+			</body>
+		</html>
+	`
+
+	data := ExtractStructuredData(html, "https://example.com/shop/")
+
+	products := data["Product"]
+	if len(products) != 1 {
+		t.Fatalf("Product = %v", products)
+	}
+	product := products[0]
+	if product["name"] != "Widget" || product["@id"] != "#p1" {
+		t.Errorf("product = %v", product)
+	}
+	if product["image"] != "https://example.com/img/widget.png" || product["url"] != "https://example.com/products/widget" {
+		t.Errorf("URL properties should be resolved, got image=%v url=%v", product["image"], product["url"])
+	}
+	if categories, ok := product["category"].([]interface{}); !ok || len(categories) != 2 {
+		t.Errorf("category = %v, want two values", product["category"])
+	}
+	offer, ok := product["offers"].(map[string]interface{})
+	if !ok || offer["@type"] != "Offer" || offer["price"] != "9.99" || offer["priceCurrency"] != "USD" {
+		t.Errorf("offers = %v", product["offers"])
+	}
+	if _, ok := data["Offer"]; ok {
+		t.Error("nested Offer should not be a top-level item")
+	}
+}
+
+func TestExtractStructuredData_RDFa(t *testing.T) {
+	html := `
+		<html>
+			<body>
+				<ol vocab="https://schema.org/" typeof="BreadcrumbList">
+					<li property="itemListElement" typeof="ListItem">
+						<a property="item" href="/docs/"><span property="name">Docs</span></a>
+						<meta property="position" content="1">
+					</li>
+				</ol>
+				<article typeof="schema:Article">
+					<h1 property="schema:headline">Hello</h1>
+					<time property="datePublished" datetime="2024-01-02">Jan 2</time>
+				</article>
+			</body>
+		</html>
+	`
+
+	data := ExtractStructuredData(html, "https://example.com/docs/page")
+
+	breadcrumbs := data["BreadcrumbList"]
+	if len(breadcrumbs) != 1 {
+		t.Fatalf("BreadcrumbList = %v", data)
+	}
+	element, ok := breadcrumbs[0]["itemListElement"].(map[string]interface{})
+	if !ok || element["@type"] != "ListItem" || element["position"] != "1" || element["item"] != "https://example.com/docs/" {
+		t.Errorf("itemListElement = %v", breadcrumbs[0]["itemListElement"])
+	}
+
+	articles := data["Article"]
+	if len(articles) != 1 || articles[0]["headline"] != "Hello" || articles[0]["datePublished"] != "2024-01-02" {
+		t.Errorf("Article = %v", articles)
+	}
+}
+
+func TestExtractStructuredData_None(t *testing.T) {
+	if data := ExtractStructuredData("<html><body><p>plain</p></body></html>", "https://example.com/"); data != nil {
+		t.Errorf("ExtractStructuredData() = %v, want nil", data)
+	}
+}
@@ -0,0 +1,167 @@
+package graph
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// 导出格式
+const (
+	FormatJSON = "json"
+	FormatDOT  = "dot"
+	FormatGEXF = "gexf"
+)
+
+// FormatFromPath 根据文件扩展名推断导出格式（未知扩展名使用 JSON）
+func FormatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".dot", ".gv":
+		return FormatDOT
+	case ".gexf":
+		return FormatGEXF
+	default:
+		return FormatJSON
+	}
+}
+
+// Write 按指定格式导出链接图
+func (g *Graph) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatJSON:
+		return g.WriteJSON(w)
+	case FormatDOT:
+		return g.WriteDOT(w)
+	case FormatGEXF:
+		return g.WriteGEXF(w)
+	default:
+		return fmt.Errorf("unknown graph format %q", format)
+	}
+}
+
+// WriteJSON 以 JSON 格式导出节点和边
+func (g *Graph) WriteJSON(w io.Writer) error {
+	output := map[string]interface{}{
+		"nodes": g.Nodes(),
+		"edges": g.Edges(),
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
+
+// WriteDOT 以 GraphViz DOT 格式导出
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph links {\n")
+	for _, node := range g.Nodes() {
+		fmt.Fprintf(&b, "  %s;\n", dotQuote(node))
+	}
+	for _, edge := range g.Edges() {
+		fmt.Fprintf(&b, "  %s -> %s [label=%s, kind=%s];\n",
+			dotQuote(edge.Source), dotQuote(edge.Target), dotQuote(edge.Text), dotQuote(string(edge.Kind)))
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotQuote 生成 DOT 格式的带引号字符串
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", " ")
+	return `"` + s + `"`
+}
+
+// GEXF 文档结构
+type gexfDocument struct {
+	XMLName xml.Name  `xml:"gexf"`
+	XMLNS   string    `xml:"xmlns,attr"`
+	Version string    `xml:"version,attr"`
+	Graph   gexfGraph `xml:"graph"`
+}
+
+type gexfGraph struct {
+	DefaultEdgeType string         `xml:"defaultedgetype,attr"`
+	Mode            string         `xml:"mode,attr"`
+	Attributes      gexfAttributes `xml:"attributes"`
+	Nodes           []gexfNode     `xml:"nodes>node"`
+	Edges           []gexfEdge     `xml:"edges>edge"`
+}
+
+type gexfAttributes struct {
+	Class      string          `xml:"class,attr"`
+	Attributes []gexfAttribute `xml:"attribute"`
+}
+
+type gexfAttribute struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type gexfNode struct {
+	ID    string `xml:"id,attr"`
+	Label string `xml:"label,attr"`
+}
+
+type gexfEdge struct {
+	ID        string         `xml:"id,attr"`
+	Source    string         `xml:"source,attr"`
+	Target    string         `xml:"target,attr"`
+	Label     string         `xml:"label,attr,omitempty"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+type gexfAttValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// WriteGEXF 以 GEXF 1.3 格式导出（可由 Gephi 打开）
+func (g *Graph) WriteGEXF(w io.Writer) error {
+	nodes := g.Nodes()
+	ids := make(map[string]string, len(nodes))
+	document := gexfDocument{
+		XMLNS:   "http://gexf.net/1.3",
+		Version: "1.3",
+		Graph: gexfGraph{
+			DefaultEdgeType: "directed",
+			Mode:            "static",
+			Attributes: gexfAttributes{
+				Class:      "edge",
+				Attributes: []gexfAttribute{{ID: "kind", Title: "kind", Type: "string"}},
+			},
+		},
+	}
+
+	for i, node := range nodes {
+		ids[node] = fmt.Sprintf("n%d", i)
+		document.Graph.Nodes = append(document.Graph.Nodes, gexfNode{ID: ids[node], Label: node})
+	}
+	for i, edge := range g.Edges() {
+		document.Graph.Edges = append(document.Graph.Edges, gexfEdge{
+			ID:        fmt.Sprintf("e%d", i),
+			Source:    ids[edge.Source],
+			Target:    ids[edge.Target],
+			Label:     edge.Text,
+			AttValues: []gexfAttValue{{For: "kind", Value: string(edge.Kind)}},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
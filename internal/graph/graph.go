@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"sync"
+
+	"flaremind/internal/models"
+)
+
+// Graph 站内链接图（有向图，节点为 URL）
+type Graph struct {
+	mu        sync.RWMutex
+	nodes     []string
+	nodeIndex map[string]int
+	edges     []models.LinkEdge
+	edgeSeen  map[edgeKey]bool
+}
+
+// edgeKey 边去重键（同一对页面之间的同类链接只记录一次）
+type edgeKey struct {
+	source string
+	target string
+	kind   models.LinkKind
+}
+
+// New 创建新的链接图
+func New() *Graph {
+	return &Graph{
+		nodeIndex: make(map[string]int),
+		edgeSeen:  make(map[edgeKey]bool),
+	}
+}
+
+// AddNode 添加节点（已存在时忽略）
+func (g *Graph) AddNode(url string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.addNode(url)
+}
+
+// AddEdge 添加一条边（源和目标节点会自动添加）
+func (g *Graph) AddEdge(edge models.LinkEdge) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	key := edgeKey{source: edge.Source, target: edge.Target, kind: edge.Kind}
+	if g.edgeSeen[key] {
+		return
+	}
+	g.edgeSeen[key] = true
+
+	g.addNode(edge.Source)
+	g.addNode(edge.Target)
+	g.edges = append(g.edges, edge)
+}
+
+// addNode 添加节点（调用方需持有写锁）
+func (g *Graph) addNode(url string) {
+	if _, ok := g.nodeIndex[url]; ok {
+		return
+	}
+	g.nodeIndex[url] = len(g.nodes)
+	g.nodes = append(g.nodes, url)
+}
+
+// Nodes 返回所有节点（按添加顺序）
+func (g *Graph) Nodes() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	nodes := make([]string, len(g.nodes))
+	copy(nodes, g.nodes)
+	return nodes
+}
+
+// Edges 返回所有边（按添加顺序）
+func (g *Graph) Edges() []models.LinkEdge {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	edges := make([]models.LinkEdge, len(g.edges))
+	copy(edges, g.edges)
+	return edges
+}
+
+// ShortestPaths 从起点出发按广度优先计算到各节点的最短发现路径
+// 返回的路径包含起点和终点，无法到达的节点不在结果中
+func (g *Graph) ShortestPaths(start string) map[string][]string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	adjacency := make(map[string][]string)
+	for _, edge := range g.edges {
+		if edge.External {
+			continue
+		}
+		adjacency[edge.Source] = append(adjacency[edge.Source], edge.Target)
+	}
+
+	parent := map[string]string{start: ""}
+	queue := []string{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range adjacency[current] {
+			if _, seen := parent[next]; seen {
+				continue
+			}
+			parent[next] = current
+			queue = append(queue, next)
+		}
+	}
+
+	paths := make(map[string][]string, len(parent))
+	for node := range parent {
+		var path []string
+		for current := node; current != ""; current = parent[current] {
+			path = append([]string{current}, path...)
+		}
+		paths[node] = path
+	}
+
+	return paths
+}
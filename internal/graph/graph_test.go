@@ -0,0 +1,84 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"flaremind/internal/models"
+)
+
+func newTestGraph() *Graph {
+	g := New()
+	g.AddEdge(models.LinkEdge{Source: "https://example.com/", Target: "https://example.com/docs", Text: "Docs", Kind: models.LinkKindAnchor})
+	g.AddEdge(models.LinkEdge{Source: "https://example.com/docs", Target: "https://example.com/docs/intro", Text: "Intro", Kind: models.LinkKindAnchor})
+	g.AddEdge(models.LinkEdge{Source: "https://example.com/", Target: "https://example.com/blog", Text: `Say "hi"`, Kind: models.LinkKindAnchor})
+	g.AddEdge(models.LinkEdge{Source: "https://example.com/blog", Target: "https://example.com/docs/intro", Kind: models.LinkKindNext})
+	g.AddEdge(models.LinkEdge{Source: "https://example.com/", Target: "https://example.com/docs", Text: "Docs again", Kind: models.LinkKindAnchor})
+	return g
+}
+
+func TestGraph(t *testing.T) {
+	g := newTestGraph()
+
+	if len(g.Nodes()) != 4 {
+		t.Errorf("Expected 4 nodes, got %d", len(g.Nodes()))
+	}
+	if len(g.Edges()) != 4 {
+		t.Errorf("Expected duplicate edge to be ignored, got %d edges", len(g.Edges()))
+	}
+
+	paths := g.ShortestPaths("https://example.com/")
+	expected := []string{"https://example.com/", "https://example.com/docs", "https://example.com/docs/intro"}
+	if strings.Join(paths["https://example.com/docs/intro"], " ") != strings.Join(expected, " ") {
+		t.Errorf("ShortestPaths() = %v, want %v", paths["https://example.com/docs/intro"], expected)
+	}
+	if len(paths["https://example.com/"]) != 1 {
+		t.Errorf("Expected start path to contain only the start URL, got %v", paths["https://example.com/"])
+	}
+	if _, ok := g.ShortestPaths("https://example.com/blog")["https://example.com/"]; ok {
+		t.Error("Expected unreachable node to be missing from paths")
+	}
+}
+
+func TestGraphExport(t *testing.T) {
+	g := newTestGraph()
+
+	var jsonOut bytes.Buffer
+	if err := g.Write(&jsonOut, FormatFromPath("graph.json")); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	var decoded struct {
+		Nodes []string          `json:"nodes"`
+		Edges []models.LinkEdge `json:"edges"`
+	}
+	if err := json.Unmarshal(jsonOut.Bytes(), &decoded); err != nil || len(decoded.Edges) != 4 {
+		t.Errorf("Expected 4 JSON edges, got %d (%v)", len(decoded.Edges), err)
+	}
+
+	var dotOut bytes.Buffer
+	if err := g.Write(&dotOut, FormatFromPath("graph.dot")); err != nil {
+		t.Fatalf("WriteDOT() error = %v", err)
+	}
+	if !strings.Contains(dotOut.String(), `"https://example.com/" -> "https://example.com/blog" [label="Say \"hi\"", kind="anchor"];`) {
+		t.Errorf("Unexpected DOT output: %s", dotOut.String())
+	}
+
+	var gexfOut bytes.Buffer
+	if err := g.Write(&gexfOut, FormatFromPath("graph.gexf")); err != nil {
+		t.Fatalf("WriteGEXF() error = %v", err)
+	}
+	var document gexfDocument
+	if err := xml.Unmarshal(gexfOut.Bytes(), &document); err != nil {
+		t.Fatalf("Failed to parse GEXF output: %v", err)
+	}
+	if len(document.Graph.Nodes) != 4 || len(document.Graph.Edges) != 4 {
+		t.Errorf("Expected 4 GEXF nodes and edges, got %d and %d", len(document.Graph.Nodes), len(document.Graph.Edges))
+	}
+
+	if err := g.Write(&bytes.Buffer{}, "svg"); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
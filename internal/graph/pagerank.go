@@ -0,0 +1,108 @@
+package graph
+
+import "math"
+
+// DefaultDamping PageRank 默认阻尼系数
+const DefaultDamping = 0.85
+
+// InDegree 计算各节点的入链数（不同来源页面数，忽略自链接）
+func (g *Graph) InDegree() map[string]int {
+	inDegree := make(map[string]int)
+	for target, sources := range g.uniqueLinks() {
+		inDegree[target] = len(sources)
+	}
+	return inDegree
+}
+
+// PageRank 计算站内 PageRank（迭代直到收敛或达到最大迭代次数）
+// 没有出链的节点将其分数平均分配给所有节点，所有分数之和为 1
+func (g *Graph) PageRank(damping float64, maxIterations int) map[string]float64 {
+	nodes := g.internalNodes()
+	n := len(nodes)
+	if n == 0 {
+		return map[string]float64{}
+	}
+
+	incoming := g.uniqueLinks()
+	outDegree := make(map[string]int)
+	for _, sources := range incoming {
+		for source := range sources {
+			outDegree[source]++
+		}
+	}
+
+	rank := make(map[string]float64, n)
+	for _, node := range nodes {
+		rank[node] = 1 / float64(n)
+	}
+
+	for iteration := 0; iteration < maxIterations; iteration++ {
+		// 悬挂节点（无出链）的分数
+		dangling := 0.0
+		for _, node := range nodes {
+			if outDegree[node] == 0 {
+				dangling += rank[node]
+			}
+		}
+
+		next := make(map[string]float64, n)
+		delta := 0.0
+		for _, node := range nodes {
+			sum := 0.0
+			for source := range incoming[node] {
+				sum += rank[source] / float64(outDegree[source])
+			}
+			next[node] = (1-damping)/float64(n) + damping*(sum+dangling/float64(n))
+			delta += math.Abs(next[node] - rank[node])
+		}
+
+		rank = next
+		if delta < 1e-9 {
+			break
+		}
+	}
+
+	return rank
+}
+
+// internalNodes 返回所有非外链目标的节点
+func (g *Graph) internalNodes() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	external := make(map[string]bool)
+	for _, edge := range g.edges {
+		if edge.External {
+			external[edge.Target] = true
+		}
+	}
+	for _, edge := range g.edges {
+		if !edge.External {
+			delete(external, edge.Target)
+			delete(external, edge.Source)
+		}
+	}
+
+	var nodes []string
+	for _, node := range g.nodes {
+		if !external[node] {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// uniqueLinks 返回目标节点 -> 来源节点集合（忽略自链接、外链和重复边）
+func (g *Graph) uniqueLinks() map[string]map[string]bool {
+	incoming := make(map[string]map[string]bool)
+	for _, edge := range g.Edges() {
+		if edge.Source == edge.Target || edge.External {
+			continue
+		}
+		if incoming[edge.Target] == nil {
+			incoming[edge.Target] = make(map[string]bool)
+		}
+		incoming[edge.Target][edge.Source] = true
+	}
+	return incoming
+}
@@ -0,0 +1,47 @@
+package graph
+
+import (
+	"math"
+	"testing"
+
+	"flaremind/internal/models"
+)
+
+func TestPageRank(t *testing.T) {
+	g := New()
+	hub := "https://example.com/docs"
+	for _, page := range []string{"https://example.com/", "https://example.com/a", "https://example.com/b"} {
+		g.AddEdge(models.LinkEdge{Source: page, Target: hub, Kind: models.LinkKindAnchor})
+		g.AddEdge(models.LinkEdge{Source: hub, Target: page, Kind: models.LinkKindAnchor})
+	}
+	g.AddEdge(models.LinkEdge{Source: "https://example.com/a", Target: hub, Kind: models.LinkKindNext})
+	g.AddEdge(models.LinkEdge{Source: hub, Target: hub, Kind: models.LinkKindAnchor})
+	g.AddEdge(models.LinkEdge{Source: hub, Target: "https://other.com/", Kind: models.LinkKindAnchor, External: true})
+
+	inDegree := g.InDegree()
+	if inDegree[hub] != 3 {
+		t.Errorf("Expected hub to have 3 in-links, got %d", inDegree[hub])
+	}
+	if inDegree["https://example.com/a"] != 1 {
+		t.Errorf("Expected page a to have 1 in-link, got %d", inDegree["https://example.com/a"])
+	}
+
+	rank := g.PageRank(DefaultDamping, 100)
+	if _, ok := rank["https://other.com/"]; ok {
+		t.Error("Expected external links to be excluded from PageRank")
+	}
+	total := 0.0
+	for _, node := range g.Nodes() {
+		total += rank[node]
+		if node != hub && rank[node] >= rank[hub] {
+			t.Errorf("Expected hub to outrank %s (%.4f >= %.4f)", node, rank[node], rank[hub])
+		}
+	}
+	if math.Abs(total-1) > 1e-6 {
+		t.Errorf("Expected ranks to sum to 1, got %.6f", total)
+	}
+
+	if len(New().PageRank(DefaultDamping, 10)) != 0 {
+		t.Error("Expected empty graph to produce empty ranks")
+	}
+}
@@ -2,9 +2,106 @@ package models
 
 // PageResult 单个页面结果
 type PageResult struct {
-	URL      string `json:"url"`
-	Markdown string `json:"markdown"`
-	Depth    int    `json:"depth"`
+	URL         string `json:"url"`
+	Markdown    string `json:"markdown"`
+	Depth       int    `json:"depth"`
+	StatusCode  int    `json:"status_code,omitempty"`  // 主文档的 HTTP 状态码
+	Canonical   string `json:"canonical,omitempty"`    // 规范 URL（rel=canonical 或 Link 头）
+	DuplicateOf string `json:"duplicate_of,omitempty"` // 与该页面共享规范 URL 的已保留页面
+
+	Fingerprint        string `json:"fingerprint,omitempty"`          // 正文文本的 SimHash 指纹（16 位十六进制）
+	NearDuplicateGroup int    `json:"near_duplicate_group,omitempty"` // 近似重复分组编号（0 表示不属于任何分组）
+	NearDuplicateOf    string `json:"near_duplicate_of,omitempty"`    // 所在分组的代表页面
+	BoilerplateRemoved int    `json:"boilerplate_removed,omitempty"`  // 从正文中移除的站点模板内容块数
+
+	Referrer      string   `json:"referrer,omitempty"`       // 最短发现路径上的上一个页面
+	DiscoveryPath []string `json:"discovery_path,omitempty"` // 从起始 URL 到该页面的最短发现路径
+
+	InLinks  int     `json:"in_links"` // 站内入链数（不同来源页面数）
+	PageRank float64 `json:"pagerank"` // 站内 PageRank 分数
+
+	Title         string   `json:"title,omitempty"`          // <title>
+	Description   string   `json:"description,omitempty"`    // meta description
+	H1            []string `json:"h1,omitempty"`             // 页面中所有 H1 的文本
+	WordCount     int      `json:"word_count"`               // 正文词数（中日韩文字每字计一词）
+	RedirectChain []string `json:"redirect_chain,omitempty"` // 到达该页面经过的重定向 URL
+	FinalURL      string   `json:"final_url,omitempty"`      // 跟随重定向后的最终 URL（发生重定向时记录）
+	InSitemap     bool     `json:"in_sitemap,omitempty"`     // 是否出现在 sitemap 中
+
+	Metadata       *PageMetadata  `json:"metadata,omitempty"`        // 作者、语言、日期、OpenGraph 等页面元数据
+	StructuredData StructuredData `json:"structured_data,omitempty"` // JSON-LD、Microdata 和 RDFa 数据（按 @type 分组）
+
+	Data map[string]interface{} `json:"data,omitempty"` // 按字段提取配置提取的数据
+
+	Profile  string `json:"profile,omitempty"`   // 提取正文时使用的站点配置
+	Platform string `json:"platform,omitempty"`  // 识别出的文档站点生成器（docusaurus、mkdocs、sphinx、gitbook、vitepress）
+	NavOrder int    `json:"nav_order,omitempty"` // 在站点导航目录中的位置（从 1 开始，0 表示不在目录中）
+}
+
+// StructuredData 按 @type 分组的结构化数据，每个对象都是 JSON-LD 形式（如 "Article"、"Product"、"BreadcrumbList"）
+type StructuredData map[string][]map[string]interface{}
+
+// PageMetadata 页面元数据
+type PageMetadata struct {
+	Author    string       `json:"author,omitempty" yaml:"author,omitempty"`
+	Language  string       `json:"lang,omitempty" yaml:"lang,omitempty"`           // <html lang> 或 Content-Language
+	Published string       `json:"published,omitempty" yaml:"published,omitempty"` // 发布时间（可解析时为 RFC 3339）
+	Modified  string       `json:"modified,omitempty" yaml:"modified,omitempty"`   // 修改时间（可解析时为 RFC 3339）
+	Favicon   string       `json:"favicon,omitempty" yaml:"favicon,omitempty"`     // 图标的绝对 URL
+	OpenGraph *OpenGraph   `json:"open_graph,omitempty" yaml:"open_graph,omitempty"`
+	Twitter   *TwitterCard `json:"twitter,omitempty" yaml:"twitter,omitempty"`
+}
+
+// OpenGraph OpenGraph（og:*）字段
+type OpenGraph struct {
+	Title       string `json:"title,omitempty" yaml:"title,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Type        string `json:"type,omitempty" yaml:"type,omitempty"`
+	URL         string `json:"url,omitempty" yaml:"url,omitempty"`
+	Image       string `json:"image,omitempty" yaml:"image,omitempty"`
+	SiteName    string `json:"site_name,omitempty" yaml:"site_name,omitempty"`
+	Locale      string `json:"locale,omitempty" yaml:"locale,omitempty"`
+}
+
+// TwitterCard Twitter 卡片（twitter:*）字段
+type TwitterCard struct {
+	Card        string `json:"card,omitempty" yaml:"card,omitempty"`
+	Title       string `json:"title,omitempty" yaml:"title,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Image       string `json:"image,omitempty" yaml:"image,omitempty"`
+	Site        string `json:"site,omitempty" yaml:"site,omitempty"`
+	Creator     string `json:"creator,omitempty" yaml:"creator,omitempty"`
+}
+
+// LinkEdge 链接图中的一条边（父页面 -> 子页面）
+type LinkEdge struct {
+	Source   string   `json:"source"`
+	Target   string   `json:"target"`
+	Text     string   `json:"text,omitempty"` // 锚文本
+	Kind     LinkKind `json:"kind"`
+	External bool     `json:"external,omitempty"` // 目标不在允许的域名内（仅在记录外链时出现）
+}
+
+// LinkKind 链接来源类型
+type LinkKind string
+
+const (
+	LinkKindAnchor    LinkKind = "anchor"    // <a href>
+	LinkKindNext      LinkKind = "next"      // rel="next" 分页链接
+	LinkKindPrev      LinkKind = "prev"      // rel="prev" 分页链接
+	LinkKindAlternate LinkKind = "alternate" // <link rel="alternate">（如其他语言版本）
+	LinkKindArea      LinkKind = "area"      // <area href>
+	LinkKindIframe    LinkKind = "iframe"    // <iframe src>
+	LinkKindFrame     LinkKind = "frame"     // <frame src>
+	LinkKindRefresh   LinkKind = "refresh"   // <meta http-equiv="refresh">
+	LinkKindDataHref  LinkKind = "data-href" // data-href 属性
+	LinkKindOnclick   LinkKind = "onclick"   // onclick="location.href=..."
+)
+
+// AllLinkKinds 所有链接来源类型
+var AllLinkKinds = []LinkKind{
+	LinkKindAnchor, LinkKindNext, LinkKindPrev, LinkKindAlternate, LinkKindArea,
+	LinkKindIframe, LinkKindFrame, LinkKindRefresh, LinkKindDataHref, LinkKindOnclick,
 }
 
 // CrawlConfig 爬取配置
@@ -16,6 +113,70 @@ type CrawlConfig struct {
 	Timeout        int     // 超时时间（秒）
 	RateLimit      float64 // 每秒最大请求数（0 表示无限制）
 	Delay          int     // 每个请求之间的延迟（毫秒）
+
+	NearDuplicateThreshold float64 // 近似重复判定的相似度阈值（0-1，0 表示不检测）
+	DropNearDuplicates     bool    // 是否从结果中移除近似重复页面（保留每组的代表页面）
+
+	BoilerplateThreshold float64 // 块出现在同一主机多少比例的页面中时视为模板内容并移除（0-1，0 表示不检测）
+	BoilerplateMinPages  int     // 主机参与模板内容学习所需的最少页面数（0 表示使用默认值）
+
+	Traps TrapConfig // 爬虫陷阱检测配置
+
+	Budgets []PageBudget // 按主机、路径前缀、深度划分的页面预算（入队时检查）
+
+	FollowLinkKinds      []LinkKind // 跟随的链接类型（为空时跟随所有类型）
+	DiscoverOnclickLinks bool       // 是否从 onclick="location.href=..." 中发现链接
+
+	RespectNofollowLinks bool // 不跟随 rel="nofollow"、"ugc"、"sponsored" 的链接
+	RespectMetaRobots    bool // 遵守 <meta name="robots"> 的 noindex/nofollow
+	RespectXRobotsTag    bool // 遵守 X-Robots-Tag 响应头的 noindex/nofollow
+
+	RecordExternalLinks bool // 是否在链接图中记录外链（不会被跟随，用于链接检查）
+
+	SitemapURL string // sitemap 地址，其中的页面会以深度 1 加入队列（为空表示不读取）
 }
 
+// PageBudget 页面预算规则：匹配的 URL 入队数量不超过 MaxPages
+type PageBudget struct {
+	PathPrefix string // 只统计该路径前缀下的页面（空表示所有路径）
+	MinDepth   int    // 只统计深度 >= MinDepth 的页面
+	PerHost    bool   // 是否按主机分别计数
+	MaxPages   int    // 最大页面数
+}
 
+// TrapConfig 爬虫陷阱检测配置（各项为 0 表示不启用该规则）
+type TrapConfig struct {
+	MaxRepeatedSegments int // 同一路径段在路径中允许出现的最大次数
+	MaxURLLength        int // URL 最大长度
+	MaxQueryVariants    int // 同一路径允许的不同查询串数量
+	MaxURLsPerTemplate  int // 同一路径模板（ID 和日期折叠后）允许的 URL 数量
+}
+
+// TrappedURL 被陷阱规则拦截的 URL
+type TrappedURL struct {
+	URL  string `json:"url"`
+	Rule string `json:"rule"`
+}
+
+// CrawlReport 爬取报告
+type CrawlReport struct {
+	TrappedURLs     []TrappedURL   `json:"trapped_urls,omitempty"`
+	BudgetExhausted map[string]int `json:"budget_exhausted,omitempty"` // 预算规则 -> 因预算耗尽跳过的 URL 数量
+	NoIndexURLs     []string       `json:"noindex_urls,omitempty"`     // 因 noindex 未保存结果的页面
+	FailedURLs      []FailedURL    `json:"failed_urls,omitempty"`      // 渲染失败的页面
+
+	ExtractionErrors []ExtractionError `json:"extraction_errors,omitempty"` // 字段提取的验证错误
+}
+
+// ExtractionError 字段提取的验证错误
+type ExtractionError struct {
+	URL   string `json:"url"`
+	Field string `json:"field"`
+	Error string `json:"error"`
+}
+
+// FailedURL 渲染失败的页面
+type FailedURL struct {
+	URL   string `json:"url"`
+	Error string `json:"error"`
+}
@@ -0,0 +1,241 @@
+package profile
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed builtin/*.yaml
+var builtinFiles embed.FS
+
+// Profile 站点提取配置，覆盖通用的正文提取算法
+// Hosts、URLs 和 Detect 至少指定一项；同时指定多项时必须全部满足
+type Profile struct {
+	Name     string   `yaml:"name"`
+	Platform string   `yaml:"platform,omitempty"` // 站点生成器（如 docusaurus、mkdocs），记录在页面结果中
+	Hosts    []string `yaml:"hosts,omitempty"`    // 主机名，"*.example.com" 匹配所有子域名
+	URLs     []string `yaml:"urls,omitempty"`     // URL 正则表达式
+	Detect   []string `yaml:"detect,omitempty"`   // 页面中存在任一选择器时匹配（用于识别平台）
+
+	Content []string `yaml:"content,omitempty"` // 正文根元素选择器，按顺序尝试，匹配多个元素时合并
+	Remove  []string `yaml:"remove,omitempty"`  // 额外移除的元素
+	Keep    []string `yaml:"keep,omitempty"`    // 即使看起来像噪音也保留的元素（如论坛的 .comments）
+	Title   []string `yaml:"title,omitempty"`   // 标题选择器，按顺序尝试
+	Date    []string `yaml:"date,omitempty"`    // 发布时间选择器，按顺序尝试（优先取 datetime/content 属性）
+	Sidebar []string `yaml:"sidebar,omitempty"` // 导航目录选择器，其中的链接按出现顺序优先入队
+
+	urlPatterns []*regexp.Regexp
+}
+
+// Set 按优先级排列的一组站点配置
+type Set struct {
+	profiles []*Profile
+}
+
+// NewSet 创建站点配置集合，前面的配置优先匹配
+func NewSet(profiles ...*Profile) *Set {
+	return &Set{profiles: profiles}
+}
+
+// Profiles 返回集合中的所有配置
+func (s *Set) Profiles() []*Profile {
+	return s.profiles
+}
+
+// Builtin 返回内置的常见平台配置（文档站点生成器、WordPress、MediaWiki、Discourse 等）
+func Builtin() []*Profile {
+	profiles, err := loadFS(builtinFiles, "builtin")
+	if err != nil {
+		// 内置配置在测试中校验，出错属于程序错误
+		panic(fmt.Sprintf("invalid builtin profile: %v", err))
+	}
+	return profiles
+}
+
+// LoadDir 加载目录中的所有 .yaml/.yml 配置文件（按文件名排序）
+func LoadDir(dir string) ([]*Profile, error) {
+	return loadFS(os.DirFS(dir), ".")
+}
+
+// loadFS 加载文件系统目录中的配置文件
+func loadFS(fsys fs.FS, dir string) ([]*Profile, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if !entry.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var profiles []*Profile
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		profile, err := Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if profile.Name == "" {
+			profile.Name = strings.TrimSuffix(name, filepath.Ext(name))
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+// Parse 解析并校验单个 YAML 配置
+func Parse(data []byte) (*Profile, error) {
+	var profile Profile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&profile); err != nil {
+		return nil, fmt.Errorf("failed to parse profile: %w", err)
+	}
+	if err := profile.compile(); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// compile 校验选择器并编译 URL 正则
+func (p *Profile) compile() error {
+	if len(p.Hosts) == 0 && len(p.URLs) == 0 && len(p.Detect) == 0 {
+		return fmt.Errorf("profile %q: at least one of hosts, urls or detect is required", p.Name)
+	}
+
+	for _, pattern := range p.URLs {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("profile %q: invalid URL pattern %q: %w", p.Name, pattern, err)
+		}
+		p.urlPatterns = append(p.urlPatterns, re)
+	}
+
+	selectors := map[string][]string{
+		"detect": p.Detect, "content": p.Content, "remove": p.Remove,
+		"keep": p.Keep, "title": p.Title, "date": p.Date, "sidebar": p.Sidebar,
+	}
+	for key, values := range selectors {
+		for _, selector := range values {
+			if _, err := cascadia.ParseGroup(selector); err != nil {
+				return fmt.Errorf("profile %q: invalid %s selector %q: %w", p.Name, key, selector, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Match 返回第一个适用于该页面的配置，没有匹配时返回 nil
+func (s *Set) Match(pageURL string, html string) *Profile {
+	if s == nil {
+		return nil
+	}
+
+	host := ""
+	if parsed, err := url.Parse(pageURL); err == nil {
+		host = strings.ToLower(parsed.Hostname())
+	}
+
+	var doc *goquery.Document
+	for _, p := range s.profiles {
+		if !p.matchesHost(host) || !p.matchesURL(pageURL) {
+			continue
+		}
+		if len(p.Detect) == 0 {
+			return p
+		}
+		if doc == nil {
+			var err error
+			if doc, err = goquery.NewDocumentFromReader(strings.NewReader(html)); err != nil {
+				return nil
+			}
+		}
+		for _, selector := range p.Detect {
+			if doc.Find(selector).Length() > 0 {
+				return p
+			}
+		}
+	}
+	return nil
+}
+
+// matchesHost 判断主机名是否匹配（未指定主机时总是匹配）
+func (p *Profile) matchesHost(host string) bool {
+	if len(p.Hosts) == 0 {
+		return true
+	}
+	for _, pattern := range p.Hosts {
+		pattern = strings.ToLower(pattern)
+		if strings.HasPrefix(pattern, "*.") {
+			if strings.HasSuffix(host, pattern[1:]) {
+				return true
+			}
+		} else if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesURL 判断 URL 是否匹配（未指定 URL 正则时总是匹配）
+func (p *Profile) matchesURL(pageURL string) bool {
+	if len(p.urlPatterns) == 0 {
+		return true
+	}
+	for _, pattern := range p.urlPatterns {
+		if pattern.MatchString(pageURL) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtractTitle 使用标题选择器提取标题，没有配置或没有匹配时返回空字符串
+func (p *Profile) ExtractTitle(doc *goquery.Document) string {
+	for _, selector := range p.Title {
+		if title := strings.Join(strings.Fields(doc.Find(selector).First().Text()), " "); title != "" {
+			return title
+		}
+	}
+	return ""
+}
+
+// ExtractDate 使用日期选择器提取发布时间（优先取 datetime 或 content 属性）
+func (p *Profile) ExtractDate(doc *goquery.Document) string {
+	for _, selector := range p.Date {
+		s := doc.Find(selector).First()
+		if s.Length() == 0 {
+			continue
+		}
+		for _, attr := range []string{"datetime", "content"} {
+			if value := strings.TrimSpace(s.AttrOr(attr, "")); value != "" {
+				return value
+			}
+		}
+		if value := strings.Join(strings.Fields(s.Text()), " "); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,158 @@
+package profile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestBuiltin(t *testing.T) {
+	profiles := Builtin()
+	if len(profiles) == 0 {
+		t.Fatal("Builtin() returned no profiles")
+	}
+	for _, p := range profiles {
+		if p.Name == "" || len(p.Content) == 0 {
+			t.Errorf("builtin profile %+v should have a name and content selectors", p)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := map[string]string{
+		"name: a\ncontent: [article]\n":                    "at least one of hosts, urls or detect",
+		"name: a\nhosts: [x.com]\ncontent: ['div[[']\n":    `invalid content selector`,
+		"name: a\nurls: ['(']\n":                           "invalid URL pattern",
+		"name: a\nhosts: [x.com]\nselector: article\n":     "selector",
+		"name: a\ndetect: ['meta[']\ncontent: [article]\n": "invalid detect selector",
+	}
+
+	for content, want := range tests {
+		if _, err := Parse([]byte(content)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Parse(%q) error = %v, want error containing %q", content, err, want)
+		}
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"b-forum.yaml": "hosts: [forum.example.com]\ncontent: ['.thread']\nkeep: ['.comments']\n",
+		"a-news.yml":   "name: news\nurls: ['/news/']\ncontent: ['.story']\n",
+		"notes.txt":    "ignored",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	profiles, err := LoadDir(dir)
+	if err != nil {
+		t.Fatalf("LoadDir() error = %v", err)
+	}
+	if len(profiles) != 2 || profiles[0].Name != "news" || profiles[1].Name != "b-forum" {
+		t.Errorf("LoadDir() = %+v, want news then b-forum (named after the file)", profiles)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "c-bad.yaml"), []byte("name: bad\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadDir(dir); err == nil || !strings.Contains(err.Error(), "c-bad.yaml") {
+		t.Errorf("LoadDir() error = %v, want error naming the file", err)
+	}
+}
+
+func TestSet_Match(t *testing.T) {
+	mustParse := func(content string) *Profile {
+		p, err := Parse([]byte(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	set := NewSet(
+		mustParse("name: docs\nhosts: ['*.example.com']\nurls: ['/docs/']\ncontent: [main]\n"),
+		mustParse("name: blog\nhosts: [blog.example.com]\ncontent: [article]\n"),
+		mustParse("name: wp\ndetect: ['meta[name=generator][content^=WordPress]']\ncontent: ['.entry-content']\n"),
+	)
+
+	wordpress := `<html><head><meta name="generator" content="WordPress 6.4"></head><body></body></html>`
+	tests := []struct {
+		url  string
+		html string
+		want string
+	}{
+		{"https://www.example.com/docs/intro", "", "docs"},
+		{"https://blog.example.com/docs/intro", "", "docs"},
+		{"https://blog.example.com/post/1", "", "blog"},
+		{"https://other.org/post/1", wordpress, "wp"},
+		{"https://other.org/post/1", "<html></html>", ""},
+	}
+
+	for _, tt := range tests {
+		name := ""
+		if p := set.Match(tt.url, tt.html); p != nil {
+			name = p.Name
+		}
+		if name != tt.want {
+			t.Errorf("Match(%s) = %q, want %q", tt.url, name, tt.want)
+		}
+	}
+
+	var nilSet *Set
+	if p := nilSet.Match("https://example.com/", ""); p != nil {
+		t.Errorf("nil set Match() = %v, want nil", p)
+	}
+}
+
+func TestBuiltin_DocsPlatforms(t *testing.T) {
+	set := NewSet(Builtin()...)
+	tests := []struct {
+		html string
+		want string
+	}{
+		{`<meta name="generator" content="Docusaurus v3.1.0">`, "docusaurus"},
+		{`<meta name="generator" content="mkdocs-1.5.3, mkdocs-material-9.5.2">`, "mkdocs"},
+		{`<script src="_static/documentation_options.js"></script>`, "sphinx"},
+		{`<meta name="generator" content="GitBook 3.2.3">`, "gitbook"},
+		{`<meta name="generator" content="VitePress v1.0.0">`, "vitepress"},
+	}
+
+	for _, tt := range tests {
+		p := set.Match("https://docs.example.com/guide/", "<html><head>"+tt.html+"</head><body></body></html>")
+		if p == nil || p.Platform != tt.want {
+			t.Errorf("Match(%s) = %v, want platform %q", tt.html, p, tt.want)
+			continue
+		}
+		if len(p.Sidebar) == 0 {
+			t.Errorf("profile %s has no sidebar selectors", p.Name)
+		}
+	}
+}
+
+func TestProfile_ExtractTitleAndDate(t *testing.T) {
+	p, err := Parse([]byte("name: a\nhosts: [x.com]\ntitle: ['.missing', 'h1.title']\ndate: ['.posted']\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`
+		<html><body>
+			<h1 class="title"> Thread   title </h1>
+			<span class="posted" title="ignored">2024-02-03 10:00</span>
+		</body></html>
+	`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if title := p.ExtractTitle(doc); title != "Thread title" {
+		t.Errorf("ExtractTitle() = %q", title)
+	}
+	if date := p.ExtractDate(doc); date != "2024-02-03 10:00" {
+		t.Errorf("ExtractDate() = %q", date)
+	}
+}
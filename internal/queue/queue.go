@@ -3,15 +3,20 @@ package queue
 import (
 	"sync"
 
+	"flaremind/internal/models"
 	"flaremind/pkg/utils"
 )
 
 // Queue URL 队列管理器
 type Queue struct {
-	mu       sync.RWMutex
-	urls     []string
-	visited  map[string]bool
+	mu         sync.RWMutex
+	urls       []string
+	visited    map[string]bool
 	normalized map[string]string // 原始 URL -> 规范化 URL 的映射
+
+	traps    *TrapDetector       // 陷阱检测器（为 nil 时不检测）
+	trapped  []models.TrappedURL // 被陷阱规则拦截的 URL
+	seenTrap map[string]bool
 }
 
 // NewQueue 创建新的队列
@@ -20,9 +25,17 @@ func NewQueue() *Queue {
 		urls:       make([]string, 0),
 		visited:    make(map[string]bool),
 		normalized: make(map[string]string),
+		seenTrap:   make(map[string]bool),
 	}
 }
 
+// SetTrapDetector 设置陷阱检测器，之后添加的 URL 会经过陷阱规则检查
+func (q *Queue) SetTrapDetector(detector *TrapDetector) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.traps = detector
+}
+
 // Add 添加 URL 到队列（如果未访问过）
 func (q *Queue) Add(url string) bool {
 	q.mu.Lock()
@@ -46,6 +59,17 @@ func (q *Queue) Add(url string) bool {
 		}
 	}
 
+	// 检查爬虫陷阱
+	if q.traps != nil {
+		if rule, trapped := q.traps.Check(normalized); trapped {
+			if !q.seenTrap[normalized] {
+				q.seenTrap[normalized] = true
+				q.trapped = append(q.trapped, models.TrappedURL{URL: normalized, Rule: rule})
+			}
+			return false
+		}
+	}
+
 	// 添加到队列
 	q.urls = append(q.urls, normalized)
 	q.normalized[url] = normalized
@@ -94,6 +118,26 @@ func (q *Queue) IsVisited(url string) bool {
 	return q.visited[normalized]
 }
 
+// Contains 检查 URL 是否已访问或已在队列中
+func (q *Queue) Contains(url string) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	normalized, err := utils.NormalizeURL(url)
+	if err != nil {
+		return false
+	}
+	if q.visited[normalized] {
+		return true
+	}
+	for _, u := range q.urls {
+		if u == normalized {
+			return true
+		}
+	}
+	return false
+}
+
 // Size 返回队列大小
 func (q *Queue) Size() int {
 	q.mu.RLock()
@@ -101,12 +145,18 @@ func (q *Queue) Size() int {
 	return len(q.urls)
 }
 
+// Trapped 返回被陷阱规则拦截的 URL 列表
+func (q *Queue) Trapped() []models.TrappedURL {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	trapped := make([]models.TrappedURL, len(q.trapped))
+	copy(trapped, q.trapped)
+	return trapped
+}
+
 // VisitedCount 返回已访问的 URL 数量
 func (q *Queue) VisitedCount() int {
 	q.mu.RLock()
 	defer q.mu.RUnlock()
 	return len(q.visited)
 }
-
-
-
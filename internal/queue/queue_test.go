@@ -36,6 +36,13 @@ func TestQueue(t *testing.T) {
 		t.Error("Expected not to pop from empty queue")
 	}
 
+	// 测试 Contains
+	q.Add("https://example.com/page3")
+	if !q.Contains("https://example.com/page3") || q.Contains("https://example.com/page4") {
+		t.Error("Expected Contains to report only queued URLs")
+	}
+	q.Pop()
+
 	// 测试 MarkVisited 和 IsVisited
 	q.Add("https://example.com/page2")
 	q.MarkVisited("https://example.com/page2")
@@ -0,0 +1,126 @@
+package queue
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"flaremind/internal/models"
+)
+
+// 爬虫陷阱规则
+const (
+	TrapRuleRepeatedSegments = "repeated-segments" // 路径段重复次数过多（如 /a/b/a/b/a/b）
+	TrapRuleURLLength        = "url-length"        // URL 过长
+	TrapRuleQueryVariants    = "query-variants"    // 同一路径的查询串变体过多（如分面搜索）
+	TrapRulePathTemplate     = "path-template"     // 同一路径模板的 URL 过多（如日历页）
+)
+
+var (
+	// 日期类路径段：2024、2024-01-02、20240102、2024_01
+	dateSegmentPattern = regexp.MustCompile(`^(19|20)\d{2}([-_.]?\d{1,2}){0,2}$`)
+	// ID 类路径段：纯数字、UUID、长十六进制串
+	idSegmentPattern = regexp.MustCompile(`^(\d+|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|[0-9a-fA-F]{16,})$`)
+)
+
+// TrapDetector 爬虫陷阱检测器
+type TrapDetector struct {
+	mu            sync.Mutex
+	config        models.TrapConfig
+	queryVariants map[string]map[string]bool // host+path -> 查询串集合
+	templateURLs  map[string]int             // host+路径模板 -> URL 数量
+}
+
+// NewTrapDetector 创建新的陷阱检测器
+func NewTrapDetector(config models.TrapConfig) *TrapDetector {
+	return &TrapDetector{
+		config:        config,
+		queryVariants: make(map[string]map[string]bool),
+		templateURLs:  make(map[string]int),
+	}
+}
+
+// Check 检查 URL 是否落入陷阱，返回命中的规则
+// 未命中时 URL 会计入查询串变体和路径模板的统计
+func (d *TrapDetector) Check(rawURL string) (string, bool) {
+	if d.config.MaxURLLength > 0 && len(rawURL) > d.config.MaxURLLength {
+		return TrapRuleURLLength, true
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", false
+	}
+
+	segments := splitPath(u.Path)
+	if d.config.MaxRepeatedSegments > 0 && maxSegmentRepeats(segments) > d.config.MaxRepeatedSegments {
+		return TrapRuleRepeatedSegments, true
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	pathKey := u.Host + u.Path
+	variants := d.queryVariants[pathKey]
+	newVariant := u.RawQuery != "" && !variants[u.RawQuery]
+	if newVariant && d.config.MaxQueryVariants > 0 && len(variants) >= d.config.MaxQueryVariants {
+		return TrapRuleQueryVariants, true
+	}
+
+	templateKey := u.Host + PathTemplate(u.Path)
+	if d.config.MaxURLsPerTemplate > 0 && d.templateURLs[templateKey] >= d.config.MaxURLsPerTemplate {
+		return TrapRulePathTemplate, true
+	}
+
+	// 记录统计
+	if newVariant {
+		if variants == nil {
+			variants = make(map[string]bool)
+			d.queryVariants[pathKey] = variants
+		}
+		variants[u.RawQuery] = true
+	}
+	d.templateURLs[templateKey]++
+
+	return "", false
+}
+
+// PathTemplate 将路径中的 ID 和日期段折叠为占位符
+// 例如：/calendar/2024-05-17/event/123 -> /calendar/{date}/event/{id}
+func PathTemplate(path string) string {
+	segments := splitPath(path)
+	for i, segment := range segments {
+		switch {
+		case dateSegmentPattern.MatchString(segment):
+			segments[i] = "{date}"
+		case idSegmentPattern.MatchString(segment):
+			segments[i] = "{id}"
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// splitPath 拆分路径为非空路径段
+func splitPath(path string) []string {
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// maxSegmentRepeats 返回路径中同一路径段出现的最大次数
+func maxSegmentRepeats(segments []string) int {
+	counts := make(map[string]int)
+	maxCount := 0
+	for _, segment := range segments {
+		counts[segment]++
+		if counts[segment] > maxCount {
+			maxCount = counts[segment]
+		}
+	}
+	return maxCount
+}
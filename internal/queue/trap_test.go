@@ -0,0 +1,78 @@
+package queue
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"flaremind/internal/models"
+)
+
+func TestTrapDetector(t *testing.T) {
+	detector := NewTrapDetector(models.TrapConfig{
+		MaxRepeatedSegments: 2,
+		MaxURLLength:        100,
+		MaxQueryVariants:    2,
+		MaxURLsPerTemplate:  3,
+	})
+
+	tests := []struct {
+		url  string
+		rule string
+	}{
+		{"https://example.com/a/b/a/b", ""},
+		{"https://example.com/a/b/a/b/a/b", TrapRuleRepeatedSegments},
+		{"https://example.com/" + strings.Repeat("x", 100), TrapRuleURLLength},
+		{"https://example.com/search?q=1", ""},
+		{"https://example.com/search?q=2", ""},
+		{"https://example.com/search?q=2", ""},
+		{"https://example.com/search?q=3", TrapRuleQueryVariants},
+	}
+
+	for _, tt := range tests {
+		rule, trapped := detector.Check(tt.url)
+		if trapped != (tt.rule != "") || rule != tt.rule {
+			t.Errorf("Check(%s) = %q, %v, want %q", tt.url, rule, trapped, tt.rule)
+		}
+	}
+
+	// 路径模板：ID 和日期折叠后计数
+	for i := 1; i <= 3; i++ {
+		if rule, trapped := detector.Check(fmt.Sprintf("https://example.com/calendar/2024-05-%02d", i)); trapped {
+			t.Errorf("Expected calendar page %d to be allowed, got rule %s", i, rule)
+		}
+	}
+	if rule, _ := detector.Check("https://example.com/calendar/2024-05-04"); rule != TrapRulePathTemplate {
+		t.Errorf("Expected path template rule, got %q", rule)
+	}
+}
+
+func TestPathTemplate(t *testing.T) {
+	tests := map[string]string{
+		"/calendar/2024-05-17/event/123":             "/calendar/{date}/event/{id}",
+		"/posts/20240517/hello":                      "/posts/{date}/hello",
+		"/item/550e8400-e29b-41d4-a716-446655440000": "/item/{id}",
+		"/docs/intro":                                "/docs/intro",
+	}
+
+	for path, expected := range tests {
+		if result := PathTemplate(path); result != expected {
+			t.Errorf("PathTemplate(%s) = %s, want %s", path, result, expected)
+		}
+	}
+}
+
+func TestQueueTrapDetector(t *testing.T) {
+	q := NewQueue()
+	q.SetTrapDetector(NewTrapDetector(models.TrapConfig{MaxRepeatedSegments: 2}))
+
+	if q.Add("https://example.com/a/a/a") {
+		t.Error("Expected trapped URL not to be added")
+	}
+	q.Add("https://example.com/a/a/a")
+
+	trapped := q.Trapped()
+	if len(trapped) != 1 || trapped[0].Rule != TrapRuleRepeatedSegments {
+		t.Errorf("Expected one trapped URL with repeated-segments rule, got %v", trapped)
+	}
+}
@@ -0,0 +1,108 @@
+package schema
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"flaremind/internal/models"
+)
+
+// 导出格式
+const (
+	FormatJSONL = "jsonl"
+	FormatCSV   = "csv"
+)
+
+// FormatFromPath 根据文件扩展名推断导出格式（未知扩展名使用 JSONL）
+func FormatFromPath(path string) string {
+	if strings.ToLower(filepath.Ext(path)) == ".csv" {
+		return FormatCSV
+	}
+	return FormatJSONL
+}
+
+// Write 按指定格式导出页面的提取结果（跳过没有数据的页面）
+func (s *Schema) Write(w io.Writer, pages []models.PageResult, format string) error {
+	switch format {
+	case FormatJSONL:
+		return WriteJSONL(w, pages)
+	case FormatCSV:
+		return WriteCSV(w, pages, s.Columns())
+	default:
+		return fmt.Errorf("unknown data format %q", format)
+	}
+}
+
+// WriteJSONL 每行输出一个页面的 URL 和提取结果
+func WriteJSONL(w io.Writer, pages []models.PageResult) error {
+	encoder := json.NewEncoder(w)
+	for _, page := range pages {
+		if page.Data == nil {
+			continue
+		}
+		record := map[string]interface{}{
+			"url":  page.URL,
+			"data": page.Data,
+		}
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteCSV 以 url 加指定字段为列输出，列表和嵌套对象编码为 JSON
+func WriteCSV(w io.Writer, pages []models.PageResult, columns []string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(append([]string{"url"}, columns...)); err != nil {
+		return err
+	}
+
+	for _, page := range pages {
+		if page.Data == nil {
+			continue
+		}
+		row := make([]string, 0, len(columns)+1)
+		row = append(row, page.URL)
+		for _, column := range columns {
+			cell, err := formatCell(page.Data[column])
+			if err != nil {
+				return fmt.Errorf("%s: field %q: %w", page.URL, column, err)
+			}
+			row = append(row, cell)
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatCell 将字段值格式化为 CSV 单元格
+func formatCell(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+}
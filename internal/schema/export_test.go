@@ -0,0 +1,58 @@
+package schema
+
+import (
+	"bytes"
+	"testing"
+
+	"flaremind/internal/models"
+)
+
+func TestWriteJSONL(t *testing.T) {
+	pages := []models.PageResult{
+		{URL: "https://example.com/a", Data: map[string]interface{}{"title": "A"}},
+		{URL: "https://example.com/b"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJSONL(&buf, pages); err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"data":{"title":"A"},"url":"https://example.com/a"}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("WriteJSONL() = %q, want %q", buf.String(), expected)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	pages := []models.PageResult{
+		{URL: "https://example.com/a", Data: map[string]interface{}{
+			"title":  "Widget, \"Pro\"",
+			"price":  9.5,
+			"count":  int64(3),
+			"tags":   []interface{}{"x", "y"},
+			"active": true,
+		}},
+		{URL: "https://example.com/b", Data: map[string]interface{}{"title": nil}},
+		{URL: "https://example.com/c"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, pages, []string{"title", "price", "count", "tags", "active"}); err != nil {
+		t.Fatal(err)
+	}
+	expected := "url,title,price,count,tags,active\n" +
+		"https://example.com/a,\"Widget, \"\"Pro\"\"\",9.5,3,\"[\"\"x\"\",\"\"y\"\"]\",true\n" +
+		"https://example.com/b,,,,,\n"
+	if buf.String() != expected {
+		t.Errorf("WriteCSV() = %q\nwant %q", buf.String(), expected)
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	if format := FormatFromPath("out/records.CSV"); format != FormatCSV {
+		t.Errorf("FormatFromPath(.CSV) = %s", format)
+	}
+	if format := FormatFromPath("out/records.jsonl"); format != FormatJSONL {
+		t.Errorf("FormatFromPath(.jsonl) = %s", format)
+	}
+}
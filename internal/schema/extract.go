@@ -0,0 +1,194 @@
+package schema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"flaremind/pkg/utils"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/antchfx/htmlquery"
+	"golang.org/x/net/html"
+)
+
+// FieldError 字段验证错误，包含页面 URL 和字段路径
+type FieldError struct {
+	URL     string
+	Field   string
+	Message string
+}
+
+// Error 实现 error 接口
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: field %q: %s", e.URL, e.Field, e.Message)
+}
+
+// Extract 使用适用于 pageURL 的规则从 HTML 中提取字段
+// pageURL 用于匹配规则和记录错误；url 类型字段的相对地址相对于 baseURL 解析（跟随重定向后的页面地址或 <base href>）。
+// 没有规则匹配时返回 nil；字段验证失败时返回已提取的数据以及所有字段错误
+func (s *Schema) Extract(rawHTML string, pageURL string, baseURL string) (map[string]interface{}, []*FieldError) {
+	rule := s.Match(pageURL)
+	if rule == nil {
+		return nil, nil
+	}
+
+	root, err := html.Parse(strings.NewReader(rawHTML))
+	if err != nil {
+		return nil, []*FieldError{{URL: pageURL, Message: fmt.Sprintf("failed to parse HTML: %v", err)}}
+	}
+
+	extractor := &fieldExtractor{pageURL: pageURL, baseURL: baseURL}
+	data := extractor.extractFields(root, rule.Fields, "")
+	return data, extractor.errors
+}
+
+// fieldExtractor 单个页面的字段提取状态
+type fieldExtractor struct {
+	pageURL string
+	baseURL string // 解析相对地址的基准地址
+	errors  []*FieldError
+}
+
+// extractFields 在 scope 范围内提取一组字段
+func (fe *fieldExtractor) extractFields(scope *html.Node, fields []*Field, prefix string) map[string]interface{} {
+	data := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		data[field.Name] = fe.extractField(scope, field, prefix+field.Name)
+	}
+	return data
+}
+
+// extractField 提取单个字段：列表字段返回数组，其他字段返回第一个值或 nil
+func (fe *fieldExtractor) extractField(scope *html.Node, field *Field, path string) interface{} {
+	nodes := field.selectNodes(scope)
+	if !field.List && len(nodes) > 1 {
+		nodes = nodes[:1]
+	}
+
+	values := make([]interface{}, 0, len(nodes))
+	for _, node := range nodes {
+		if len(field.Fields) > 0 {
+			values = append(values, fe.extractFields(node, field.Fields, path+"."))
+			continue
+		}
+
+		value, err := field.value(node, fe.baseURL)
+		if err != nil {
+			fe.addError(path, err.Error())
+			continue
+		}
+		if value != nil {
+			values = append(values, value)
+		}
+	}
+
+	if len(values) == 0 && field.Required {
+		if len(nodes) == 0 {
+			fe.addError(path, "required value missing: selector matched nothing")
+		} else {
+			fe.addError(path, "required value missing")
+		}
+	}
+
+	if field.List {
+		return values
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return values[0]
+}
+
+// addError 记录字段错误
+func (fe *fieldExtractor) addError(path, message string) {
+	fe.errors = append(fe.errors, &FieldError{URL: fe.pageURL, Field: path, Message: message})
+}
+
+// selectNodes 返回字段选择器在 scope 内匹配的节点
+func (f *Field) selectNodes(scope *html.Node) []*html.Node {
+	switch {
+	case f.css != nil:
+		return cascadia.QueryAll(scope, f.css)
+	case f.xpath != nil:
+		return htmlquery.QuerySelectorAll(scope, f.xpath)
+	default:
+		return []*html.Node{scope}
+	}
+}
+
+// value 取节点的原始值并进行正则后处理和类型转换；没有值时返回 nil
+func (f *Field) value(node *html.Node, baseURL string) (interface{}, error) {
+	var raw string
+	switch f.Attr {
+	case "", AttrText:
+		raw = htmlquery.InnerText(node)
+	case AttrHTML:
+		raw = htmlquery.OutputHTML(node, false)
+	default:
+		raw = htmlquery.SelectAttr(node, f.Attr)
+	}
+	if f.Attr != AttrHTML {
+		raw = strings.Join(strings.Fields(raw), " ")
+	}
+
+	if f.regex != nil && raw != "" {
+		match := f.regex.FindStringSubmatch(raw)
+		switch {
+		case match == nil:
+			raw = ""
+		case len(match) > 1:
+			raw = match[1]
+		default:
+			raw = match[0]
+		}
+	}
+
+	if raw == "" {
+		return nil, nil
+	}
+	return convert(raw, f.Type, baseURL)
+}
+
+// convert 将字符串转换为字段类型
+func convert(raw, fieldType, baseURL string) (interface{}, error) {
+	switch fieldType {
+	case TypeInt:
+		value, err := strconv.ParseInt(numeric(raw), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert %q to int", raw)
+		}
+		return value, nil
+	case TypeFloat:
+		value, err := strconv.ParseFloat(numeric(raw), 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert %q to float", raw)
+		}
+		return value, nil
+	case TypeBool:
+		switch strings.ToLower(raw) {
+		case "yes", "y", "on":
+			return true, nil
+		case "no", "n", "off":
+			return false, nil
+		}
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert %q to bool", raw)
+		}
+		return value, nil
+	case TypeURL:
+		value, err := utils.ResolveURL(baseURL, raw)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve URL %q: %v", raw, err)
+		}
+		return value, nil
+	default:
+		return raw, nil
+	}
+}
+
+// numeric 去除数字中的千位分隔符和空白
+func numeric(raw string) string {
+	return strings.NewReplacer(",", "", " ", "", "\u00a0", "").Replace(raw)
+}
@@ -0,0 +1,136 @@
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const productHTML = `
+<html>
+	<body>
+		<h1> Widget  Pro </h1>
+		<span class="price">Price: 1,299.50 USD</span>
+		<span class="stock">yes</span>
+		<span class="reviews">12 reviews</span>
+		<div class="gallery">
+			<img src="/img/1.png">
+			<img src="/img/2.png">
+		</div>
+		<table class="specs">
+			<tr><th>Weight</th><td>1 kg</td></tr>
+			<tr><th>Color</th><td>Red</td></tr>
+		</table>
+	</body>
+</html>
+`
+
+func compileSchema(t *testing.T, rules ...*Rule) *Schema {
+	t.Helper()
+	s := &Schema{Rules: rules}
+	if err := s.Compile(); err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	return s
+}
+
+func TestSchema_Extract(t *testing.T) {
+	s := compileSchema(t, &Rule{
+		Name: "product",
+		Fields: []*Field{
+			{Name: "title", CSS: "h1", Required: true},
+			{Name: "price", XPath: `//span[@class="price"]`, Regex: `([0-9][0-9.,]*)`, Type: TypeFloat},
+			{Name: "in_stock", CSS: ".stock", Type: TypeBool},
+			{Name: "reviews", CSS: ".reviews", Regex: `\d+`, Type: TypeInt},
+			{Name: "images", CSS: ".gallery img", Attr: "src", List: true, Type: TypeURL},
+			{Name: "specs", CSS: "table.specs tr", List: true, Fields: []*Field{
+				{Name: "key", CSS: "th"},
+				{Name: "value", XPath: "./td"},
+			}},
+			{Name: "subtitle", CSS: "h2"},
+		},
+	})
+
+	data, errs := s.Extract(productHTML, "https://shop.example.com/products/1", "https://shop.example.com/products/1")
+	if len(errs) > 0 {
+		t.Fatalf("Extract() errors = %v", errs)
+	}
+
+	expected := map[string]interface{}{
+		"title":    "Widget Pro",
+		"price":    1299.5,
+		"in_stock": true,
+		"reviews":  int64(12),
+		"images":   []interface{}{"https://shop.example.com/img/1.png", "https://shop.example.com/img/2.png"},
+		"specs": []interface{}{
+			map[string]interface{}{"key": "Weight", "value": "1 kg"},
+			map[string]interface{}{"key": "Color", "value": "Red"},
+		},
+		"subtitle": nil,
+	}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Extract() = %#v\nwant %#v", data, expected)
+	}
+}
+
+func TestSchema_ExtractErrors(t *testing.T) {
+	s := compileSchema(t, &Rule{
+		Name: "product",
+		Fields: []*Field{
+			{Name: "sku", CSS: ".sku", Required: true},
+			{Name: "stock", CSS: ".stock", Type: TypeInt},
+			{Name: "specs", CSS: "table.specs", Fields: []*Field{
+				{Name: "missing", CSS: "caption", Required: true},
+			}},
+		},
+	})
+
+	pageURL := "https://shop.example.com/products/1"
+	data, errs := s.Extract(productHTML, pageURL, pageURL)
+	if len(errs) != 3 {
+		t.Fatalf("Extract() errors = %v, want 3", errs)
+	}
+
+	fields := make([]string, 0, len(errs))
+	for _, err := range errs {
+		if err.URL != pageURL {
+			t.Errorf("error URL = %q, want %q", err.URL, pageURL)
+		}
+		if !strings.Contains(err.Error(), pageURL) || !strings.Contains(err.Error(), err.Field) {
+			t.Errorf("error message %q should name the URL and field", err.Error())
+		}
+		fields = append(fields, err.Field)
+	}
+	if strings.Join(fields, ",") != "sku,stock,specs.missing" {
+		t.Errorf("error fields = %v", fields)
+	}
+	if data["sku"] != nil || data["stock"] != nil {
+		t.Errorf("invalid fields should be nil, got %v", data)
+	}
+}
+
+func TestSchema_ExtractNoMatchingRule(t *testing.T) {
+	s := compileSchema(t, &Rule{Name: "product", URLs: []string{"/products/"}, Fields: []*Field{{Name: "title", CSS: "h1"}}})
+
+	data, errs := s.Extract(productHTML, "https://shop.example.com/about", "https://shop.example.com/about")
+	if data != nil || errs != nil {
+		t.Errorf("Extract() = %v, %v; want nil, nil", data, errs)
+	}
+}
+
+func TestSchema_ExtractResolvesAgainstBaseURL(t *testing.T) {
+	s := compileSchema(t, &Rule{
+		Name:   "docs",
+		URLs:   []string{"/docs$"},
+		Fields: []*Field{{Name: "next", CSS: "a", Attr: "href", Type: TypeURL}},
+	})
+
+	// 请求的 /docs 重定向到 /docs/：规则按请求的 URL 匹配，相对地址按最终地址解析
+	data, errs := s.Extract(`<a href="install">Install</a>`, "https://example.com/docs", "https://example.com/docs/")
+	if len(errs) > 0 {
+		t.Fatalf("Extract() errors = %v", errs)
+	}
+	if data["next"] != "https://example.com/docs/install" {
+		t.Errorf("next = %v, want https://example.com/docs/install", data["next"])
+	}
+}
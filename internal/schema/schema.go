@@ -0,0 +1,208 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/antchfx/xpath"
+	"gopkg.in/yaml.v3"
+)
+
+// 字段类型
+const (
+	TypeString = "string"
+	TypeInt    = "int"
+	TypeFloat  = "float"
+	TypeBool   = "bool"
+	TypeURL    = "url" // 相对地址按页面 URL 解析为绝对地址
+)
+
+// 特殊的属性名
+const (
+	AttrText = "text" // 元素文本（默认）
+	AttrHTML = "html" // 元素内部 HTML
+)
+
+// Schema 字段提取配置，由若干规则组成，每个页面使用第一个匹配的规则
+type Schema struct {
+	Rules []*Rule `json:"rules" yaml:"rules"`
+}
+
+// Rule 适用于一组 URL 的字段定义
+type Rule struct {
+	Name   string   `json:"name" yaml:"name"`
+	URLs   []string `json:"urls,omitempty" yaml:"urls,omitempty"` // URL 正则表达式，为空时适用于所有页面
+	Fields []*Field `json:"fields" yaml:"fields"`
+
+	urlPatterns []*regexp.Regexp
+}
+
+// Field 字段定义
+// CSS 和 XPath 最多指定一个；都未指定时使用当前元素（适用于嵌套对象中的字段）
+type Field struct {
+	Name     string   `json:"name" yaml:"name"`
+	CSS      string   `json:"css,omitempty" yaml:"css,omitempty"`
+	XPath    string   `json:"xpath,omitempty" yaml:"xpath,omitempty"`
+	Attr     string   `json:"attr,omitempty" yaml:"attr,omitempty"`         // 取值的属性名，text（默认）或 html
+	List     bool     `json:"list,omitempty" yaml:"list,omitempty"`         // 返回所有匹配元素的值
+	Type     string   `json:"type,omitempty" yaml:"type,omitempty"`         // string（默认）、int、float、bool、url
+	Regex    string   `json:"regex,omitempty" yaml:"regex,omitempty"`       // 后处理正则，有分组时取第一个分组
+	Required bool     `json:"required,omitempty" yaml:"required,omitempty"` // 没有值时报告验证错误
+	Fields   []*Field `json:"fields,omitempty" yaml:"fields,omitempty"`     // 嵌套对象的字段，在匹配元素内提取
+
+	css   cascadia.Sel
+	xpath *xpath.Expr
+	regex *regexp.Regexp
+}
+
+// Load 从 YAML（.yaml/.yml）或 JSON（.json）文件加载并校验字段提取配置
+func Load(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var schema Schema
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&schema)
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&schema)
+	default:
+		return nil, fmt.Errorf("unsupported schema file %s: use .yaml, .yml or .json", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schema %s: %w", path, err)
+	}
+
+	if err := schema.Compile(); err != nil {
+		return nil, fmt.Errorf("invalid schema %s: %w", path, err)
+	}
+	return &schema, nil
+}
+
+// Compile 校验配置并编译选择器和正则表达式，错误信息包含规则名和字段路径
+func (s *Schema) Compile() error {
+	if len(s.Rules) == 0 {
+		return fmt.Errorf("no rules defined")
+	}
+
+	for i, rule := range s.Rules {
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule%d", i+1)
+		}
+		rule.urlPatterns = nil
+		for _, pattern := range rule.URLs {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("rule %q: invalid URL pattern %q: %w", rule.Name, pattern, err)
+			}
+			rule.urlPatterns = append(rule.urlPatterns, re)
+		}
+		if len(rule.Fields) == 0 {
+			return fmt.Errorf("rule %q: no fields defined", rule.Name)
+		}
+		if err := compileFields(rule.Fields, ""); err != nil {
+			return fmt.Errorf("rule %q: %w", rule.Name, err)
+		}
+	}
+	return nil
+}
+
+// compileFields 递归校验并编译字段
+func compileFields(fields []*Field, prefix string) error {
+	seen := make(map[string]bool)
+	for _, field := range fields {
+		if field.Name == "" {
+			return fmt.Errorf("field without name under %q", strings.TrimSuffix(prefix, "."))
+		}
+		path := prefix + field.Name
+		if seen[field.Name] {
+			return fmt.Errorf("field %q: defined more than once", path)
+		}
+		seen[field.Name] = true
+
+		if field.CSS != "" && field.XPath != "" {
+			return fmt.Errorf("field %q: css and xpath are mutually exclusive", path)
+		}
+		if field.CSS != "" {
+			sel, err := cascadia.Parse(field.CSS)
+			if err != nil {
+				return fmt.Errorf("field %q: invalid css selector %q: %w", path, field.CSS, err)
+			}
+			field.css = sel
+		}
+		if field.XPath != "" {
+			expr, err := xpath.Compile(field.XPath)
+			if err != nil {
+				return fmt.Errorf("field %q: invalid xpath %q: %w", path, field.XPath, err)
+			}
+			field.xpath = expr
+		}
+		if field.Regex != "" {
+			re, err := regexp.Compile(field.Regex)
+			if err != nil {
+				return fmt.Errorf("field %q: invalid regex %q: %w", path, field.Regex, err)
+			}
+			field.regex = re
+		}
+
+		switch field.Type {
+		case "":
+			field.Type = TypeString
+		case TypeString, TypeInt, TypeFloat, TypeBool, TypeURL:
+		default:
+			return fmt.Errorf("field %q: unknown type %q", path, field.Type)
+		}
+
+		if len(field.Fields) > 0 {
+			if field.Attr != "" || field.Regex != "" || field.Type != TypeString {
+				return fmt.Errorf("field %q: attr, regex and type cannot be used with nested fields", path)
+			}
+			if err := compileFields(field.Fields, path+"."); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Match 返回适用于该 URL 的第一个规则，没有匹配时返回 nil
+func (s *Schema) Match(pageURL string) *Rule {
+	for _, rule := range s.Rules {
+		if len(rule.urlPatterns) == 0 {
+			return rule
+		}
+		for _, pattern := range rule.urlPatterns {
+			if pattern.MatchString(pageURL) {
+				return rule
+			}
+		}
+	}
+	return nil
+}
+
+// Columns 返回所有规则顶层字段名的并集（按首次出现的顺序），用于 CSV 导出
+func (s *Schema) Columns() []string {
+	var columns []string
+	seen := make(map[string]bool)
+	for _, rule := range s.Rules {
+		for _, field := range rule.Fields {
+			if !seen[field.Name] {
+				seen[field.Name] = true
+				columns = append(columns, field.Name)
+			}
+		}
+	}
+	return columns
+}
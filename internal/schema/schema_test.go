@@ -0,0 +1,93 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSchemaFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_YAMLAndJSON(t *testing.T) {
+	yamlPath := writeSchemaFile(t, "products.yaml", `
+rules:
+  - name: product
+    urls: ["/products/"]
+    fields:
+      - name: title
+        css: h1
+        required: true
+      - name: price
+        xpath: //span[@class="price"]
+        regex: '([0-9.,]+)'
+        type: float
+`)
+	jsonPath := writeSchemaFile(t, "products.json", `{"rules": [{"name": "product", "fields": [{"name": "title", "css": "h1"}]}]}`)
+
+	for _, path := range []string{yamlPath, jsonPath} {
+		s, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load(%s) error = %v", filepath.Base(path), err)
+		}
+		if len(s.Rules) != 1 || s.Rules[0].Fields[0].Name != "title" {
+			t.Errorf("Load(%s) = %+v", filepath.Base(path), s.Rules)
+		}
+	}
+}
+
+func TestLoad_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"unknown key", "rules:\n  - name: r\n    fields:\n      - name: a\n        selector: h1\n", "selector"},
+		{"bad css", "rules:\n  - name: r\n    fields:\n      - name: title\n        css: 'h1[['\n", `field "title": invalid css selector`},
+		{"bad xpath", "rules:\n  - name: r\n    fields:\n      - name: title\n        xpath: '//h1[['\n", `field "title": invalid xpath`},
+		{"bad regex", "rules:\n  - name: r\n    fields:\n      - name: price\n        css: .p\n        regex: '('\n", `field "price": invalid regex`},
+		{"bad type", "rules:\n  - name: r\n    fields:\n      - name: price\n        css: .p\n        type: money\n", `field "price": unknown type "money"`},
+		{"css and xpath", "rules:\n  - name: r\n    fields:\n      - name: a\n        css: h1\n        xpath: //h1\n", "mutually exclusive"},
+		{"nested path", "rules:\n  - name: r\n    fields:\n      - name: specs\n        css: tr\n        fields:\n          - name: key\n            css: 'th[['\n", `field "specs.key"`},
+		{"bad url pattern", "rules:\n  - name: r\n    urls: ['(']\n    fields:\n      - name: a\n        css: h1\n", `rule "r": invalid URL pattern`},
+		{"no rules", "rules: []\n", "no rules"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeSchemaFile(t, "schema.yaml", tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load() error = %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchema_MatchAndColumns(t *testing.T) {
+	s := &Schema{Rules: []*Rule{
+		{Name: "product", URLs: []string{`/products/\d+$`}, Fields: []*Field{{Name: "title"}, {Name: "price"}}},
+		{Name: "fallback", Fields: []*Field{{Name: "title"}, {Name: "author"}}},
+	}}
+	if err := s.Compile(); err != nil {
+		t.Fatal(err)
+	}
+
+	if rule := s.Match("https://shop.example.com/products/42"); rule == nil || rule.Name != "product" {
+		t.Errorf("Match(product) = %v", rule)
+	}
+	if rule := s.Match("https://shop.example.com/about"); rule == nil || rule.Name != "fallback" {
+		t.Errorf("Match(about) = %v", rule)
+	}
+
+	columns := strings.Join(s.Columns(), ",")
+	if columns != "title,price,author" {
+		t.Errorf("Columns() = %s", columns)
+	}
+}
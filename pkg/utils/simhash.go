@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// simHashShingleSize SimHash 使用的词组（shingle）长度
+const simHashShingleSize = 3
+
+// SimHash 计算文本的 64 位 SimHash 指纹
+// 相似的文本得到的指纹汉明距离较小
+func SimHash(text string) uint64 {
+	tokens := tokenize(text)
+	if len(tokens) == 0 {
+		return 0
+	}
+
+	// 统计 shingle 出现次数作为权重
+	weights := make(map[string]int)
+	if len(tokens) < simHashShingleSize {
+		weights[strings.Join(tokens, " ")]++
+	} else {
+		for i := 0; i+simHashShingleSize <= len(tokens); i++ {
+			weights[strings.Join(tokens[i:i+simHashShingleSize], " ")]++
+		}
+	}
+
+	var vector [64]int
+	for shingle, weight := range weights {
+		h := fnv.New64a()
+		h.Write([]byte(shingle))
+		sum := h.Sum64()
+		for bit := 0; bit < 64; bit++ {
+			if sum&(1<<uint(bit)) != 0 {
+				vector[bit] += weight
+			} else {
+				vector[bit] -= weight
+			}
+		}
+	}
+
+	var fingerprint uint64
+	for bit := 0; bit < 64; bit++ {
+		if vector[bit] > 0 {
+			fingerprint |= 1 << uint(bit)
+		}
+	}
+
+	return fingerprint
+}
+
+// HammingDistance 计算两个指纹之间的汉明距离
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// SimHashSimilarity 计算两个指纹的相似度（0 到 1，1 表示完全相同）
+func SimHashSimilarity(a, b uint64) float64 {
+	return 1 - float64(HammingDistance(a, b))/64
+}
+
+// FormatSimHash 将指纹格式化为 16 位十六进制字符串
+func FormatSimHash(fingerprint uint64) string {
+	return fmt.Sprintf("%016x", fingerprint)
+}
+
+// ParseSimHash 解析十六进制格式的指纹
+func ParseSimHash(s string) (uint64, error) {
+	return strconv.ParseUint(s, 16, 64)
+}
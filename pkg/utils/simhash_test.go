@@ -0,0 +1,49 @@
+package utils
+
+import "testing"
+
+func TestSimHash(t *testing.T) {
+	article := "The quick brown fox jumps over the lazy dog while the farmer watches from the old wooden fence near the barn"
+	variant := "The quick brown fox jumps over the lazy dog while the farmer watches from the old wooden fence near the house"
+	other := "Go is an open source programming language that makes it simple to build secure, scalable systems"
+
+	if SimHash(article) != SimHash(article) {
+		t.Error("Expected identical text to produce identical fingerprints")
+	}
+
+	similar := SimHashSimilarity(SimHash(article), SimHash(variant))
+	different := SimHashSimilarity(SimHash(article), SimHash(other))
+	if similar <= different {
+		t.Errorf("Expected near-duplicate similarity (%.2f) to exceed unrelated similarity (%.2f)", similar, different)
+	}
+	if similar < 0.8 {
+		t.Errorf("Expected near-duplicate similarity >= 0.8, got %.2f", similar)
+	}
+
+	if SimHash("") != 0 {
+		t.Error("Expected empty text to produce zero fingerprint")
+	}
+}
+
+func TestSimHashCJK(t *testing.T) {
+	a := SimHash("今天北京天气晴朗，适合外出游玩，公园里有很多人在散步和锻炼身体")
+	b := SimHash("今天北京天气晴朗，适合外出游玩，公园里有很多人在散步和跑步锻炼")
+	c := SimHash("央行宣布下调存款准备金率，释放长期资金约一万亿元支持实体经济")
+
+	if SimHashSimilarity(a, b) <= SimHashSimilarity(a, c) {
+		t.Errorf("Expected CJK near-duplicates to be more similar than unrelated text")
+	}
+}
+
+func TestFormatParseSimHash(t *testing.T) {
+	fingerprint := uint64(0x00ab12cd34ef5678)
+	formatted := FormatSimHash(fingerprint)
+	if formatted != "00ab12cd34ef5678" {
+		t.Errorf("FormatSimHash() = %s, want 00ab12cd34ef5678", formatted)
+	}
+
+	parsed, err := ParseSimHash(formatted)
+	if err != nil || parsed != fingerprint {
+		t.Errorf("ParseSimHash() = %x, %v, want %x", parsed, err, fingerprint)
+	}
+}
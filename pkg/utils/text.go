@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// CountWords 统计文本词数（中日韩文字每个字计为一个词）
+func CountWords(text string) int {
+	return len(tokenize(text))
+}
+
+// tokenize 将文本切分为小写词元，中日韩文字按单字切分
+func tokenize(text string) []string {
+	var tokens []string
+	var current strings.Builder
+
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case isCJK(r):
+			flush()
+			tokens = append(tokens, string(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			current.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// isCJK 判断字符是否为中日韩文字
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// CharCount 统计文本字符数（按字素近似：组合标记、变体选择符、肤色修饰符及零宽连接符连接的字符不单独计数）
+func CharCount(text string) int {
+	count := 0
+	joined := false
+	for _, r := range text {
+		switch {
+		case r == zeroWidthJoiner:
+			joined = true
+			continue
+		case unicode.In(r, unicode.Mn, unicode.Me, unicode.Variation_Selector), r >= 0x1F3FB && r <= 0x1F3FF:
+			continue
+		case joined:
+			joined = false
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+// CJKRatio 返回中日韩文字在全部字母和数字中的占比
+func CJKRatio(text string) float64 {
+	cjk, letters := 0, 0
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			cjk++
+			letters++
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			letters++
+		}
+	}
+	if letters == 0 {
+		return 0
+	}
+	return float64(cjk) / float64(letters)
+}
+
+// CountCommas 统计逗号数量，包括全角逗号、顿号等中日韩及其他语言的逗号
+func CountCommas(text string) int {
+	count := 0
+	for _, r := range text {
+		if strings.ContainsRune(commaRunes, r) {
+			count++
+		}
+	}
+	return count
+}
+
+const (
+	zeroWidthJoiner = '\u200d'
+	// commaRunes 视为逗号的字符
+	commaRunes = ",،﹐︐︑⹁⸴⸲，、﹑､"
+)
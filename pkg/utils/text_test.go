@@ -0,0 +1,65 @@
+package utils
+
+import "testing"
+
+func TestCountWords(t *testing.T) {
+	tests := map[string]int{
+		"Hello, world!":   2,
+		"Go 语言教程":         5,
+		"  \n\t ":         0,
+		"it's 2024-05-17": 5,
+	}
+
+	for text, expected := range tests {
+		if result := CountWords(text); result != expected {
+			t.Errorf("CountWords(%q) = %d, want %d", text, result, expected)
+		}
+	}
+}
+
+func TestCharCount(t *testing.T) {
+	tests := map[string]int{
+		"hello":                5,
+		"中文内容":                 4,
+		"e\u0301":              1,
+		"\U0001F44D\U0001F3FD": 1,
+		"\U0001F468\u200d\U0001F469\u200d\U0001F467": 1,
+		"\u263a\ufe0f": 1,
+	}
+
+	for text, expected := range tests {
+		if result := CharCount(text); result != expected {
+			t.Errorf("CharCount(%q) = %d, want %d", text, result, expected)
+		}
+	}
+}
+
+func TestCJKRatio(t *testing.T) {
+	if ratio := CJKRatio("中文"); ratio != 1 {
+		t.Errorf("CJKRatio(中文) = %v, want 1", ratio)
+	}
+	if ratio := CJKRatio("abc"); ratio != 0 {
+		t.Errorf("CJKRatio(abc) = %v, want 0", ratio)
+	}
+	if ratio := CJKRatio("Go语言"); ratio != 0.5 {
+		t.Errorf("CJKRatio(Go语言) = %v, want 0.5", ratio)
+	}
+	if ratio := CJKRatio("  ,. "); ratio != 0 {
+		t.Errorf("CJKRatio(punctuation) = %v, want 0", ratio)
+	}
+}
+
+func TestCountCommas(t *testing.T) {
+	tests := map[string]int{
+		"a, b, c":        2,
+		"苹果、香蕉，橘子":       2,
+		"no commas here": 0,
+		"ﾃｽﾄ､ﾃｽﾄ":        1,
+	}
+
+	for text, expected := range tests {
+		if result := CountCommas(text); result != expected {
+			t.Errorf("CountCommas(%q) = %d, want %d", text, result, expected)
+		}
+	}
+}